@@ -0,0 +1,244 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// tailSeq 用于为每个 tail 请求生成唯一的 channel 名称
+var tailSeq atomic.Uint64
+
+// levelsResponse 是 GET /levels 的响应体
+type levelsResponse struct {
+	Global  string            `json:"global"`
+	Loggers map[string]string `json:"loggers"`
+}
+
+// loggerLevelResponse 是 /levels/{name} 的响应体
+type loggerLevelResponse struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+// NewAdminHandler returns an http.Handler for runtime log administration:
+//
+//	GET    /levels          global level and effective level of every named logger
+//	PUT    /levels          set the global level
+//	GET    /levels/{name}   effective level of a named logger
+//	PUT    /levels/{name}   set the level of a named logger
+//	DELETE /levels/{name}   remove the level override of a named logger
+//	GET    /channels        named channel statistics
+//	GET    /tail            live tail as Server-Sent Events or newline-delimited JSON
+//
+// PUT accepts either a plain level name or {"level":"debug"} as body.
+// /tail accepts the query parameters format (sse or ndjson, defaulting to the
+// Accept header), buffer (channel buffer size) and the EntryFilter parameters
+// level, prefix, logger and text.
+// Use http.StripPrefix to mount the handler under a sub path.
+func NewAdminHandler() http.Handler {
+	return http.HandlerFunc(serveAdmin)
+}
+
+func serveAdmin(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+
+	switch {
+	case path == "levels":
+		serveLevels(w, r)
+	case strings.HasPrefix(path, "levels/"):
+		serveLoggerLevel(w, r, strings.TrimPrefix(path, "levels/"))
+	case path == "channels":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		writeJSON(w, GetChannelStats())
+	case path == "tail":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		serveTail(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func serveLevels(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		loggers := LoggerLevels()
+		for _, name := range LoggerNames() {
+			loggers[name] = GetLoggerLevel(name)
+		}
+		writeJSON(w, levelsResponse{Global: GetLevel(), Loggers: loggers})
+	case http.MethodPut:
+		level, err := readLevelBody(r)
+		if err == nil {
+			err = SetLevel(level)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logNotice("log level changed to %s via admin handler", GetLevel())
+		writeJSON(w, levelsResponse{Global: GetLevel(), Loggers: LoggerLevels()})
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut)
+	}
+}
+
+func serveLoggerLevel(w http.ResponseWriter, r *http.Request, name string) {
+	if name == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		level, err := readLevelBody(r)
+		if err == nil {
+			err = SetLoggerLevel(name, level)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logNotice("log level of %s changed to %s via admin handler", name, GetLoggerLevel(name))
+	case http.MethodDelete:
+		SetLoggerLevel(name, "")
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+		return
+	}
+
+	writeJSON(w, loggerLevelResponse{Name: name, Level: GetLoggerLevel(name)})
+}
+
+// serveTail 订阅一个临时 channel，将条目持续写入响应直到客户端断开
+func serveTail(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	filter, err := filterFromQuery(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	size, err := bufferFromQuery(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sse := query.Get("format") == "sse" ||
+		(query.Get("format") == "" && strings.Contains(r.Header.Get("Accept"), "text/event-stream"))
+
+	name := fmt.Sprintf("tail-%d", tailSeq.Add(1))
+	ch := GetLogChannelWithConfig(name, LogChannelConfig{
+		BufferSize: size,
+		Timeout:    100 * time.Millisecond,
+		Level:      filter.Level,
+	})
+	defer RemoveLogChannel(name)
+
+	if sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case entry, ok := <-ch:
+			if !ok {
+				return
+			}
+			if !filter.Match(entry) {
+				continue
+			}
+
+			data, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
+			if sse {
+				_, err = fmt.Fprintf(w, "event: log\ndata: %s\n\n", data)
+			} else {
+				_, err = fmt.Fprintf(w, "%s\n", data)
+			}
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// bufferFromQuery 读取 buffer 查询参数，缺省为全局 channel 缓冲区大小
+func bufferFromQuery(query url.Values) (int, error) {
+	s := query.Get("buffer")
+	if s == "" {
+		return currentConfig().bufferSize, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid buffer size %q", s)
+	}
+	return n, nil
+}
+
+// readLevelBody 从请求体读取级别名称，支持纯文本或 {"level":"..."}
+func readLevelBody(r *http.Request) (string, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+
+	text := strings.TrimSpace(string(body))
+	if strings.HasPrefix(text, "{") {
+		var req struct {
+			Level string `json:"level"`
+		}
+		if err := json.Unmarshal([]byte(text), &req); err != nil {
+			return "", fmt.Errorf("invalid request body: %w", err)
+		}
+		text = req.Level
+	}
+
+	if text == "" {
+		return "", fmt.Errorf("level must not be empty")
+	}
+	return text, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func methodNotAllowed(w http.ResponseWriter, methods ...string) {
+	sort.Strings(methods)
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+}
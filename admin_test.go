@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdminLevels(t *testing.T) {
+	handler := NewAdminHandler()
+	defer SetLevel(LevelDebug)
+	defer SetLoggerLevel("admin-db", "")
+
+	req := httptest.NewRequest(http.MethodPut, "/levels", strings.NewReader("warn"))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || GetLevel() != LevelWarn {
+		t.Fatalf("PUT /levels failed: %d %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/levels/admin-db", strings.NewReader(`{"level":"trace"}`))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || GetLoggerLevel("admin-db") != LevelTrace {
+		t.Fatalf("PUT /levels/admin-db failed: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/levels", nil))
+	var levels levelsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &levels); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if levels.Global != LevelWarn || levels.Loggers["admin-db"] != LevelTrace {
+		t.Errorf("Unexpected levels: %+v", levels)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/levels/admin-db", strings.NewReader("loud"))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown level, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/levels/admin-db", nil))
+	if GetLoggerLevel("admin-db") != LevelWarn {
+		t.Errorf("DELETE should fall back to global level, got %s", GetLoggerLevel("admin-db"))
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/levels", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", rec.Code)
+	}
+}
+
+func TestAdminChannels(t *testing.T) {
+	GetLogChannelWithConfig("admin-stats", LogChannelConfig{BufferSize: 1})
+	defer RemoveLogChannel("admin-stats")
+
+	Info("first")
+	Info("second")
+
+	rec := httptest.NewRecorder()
+	NewAdminHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/channels", nil))
+
+	var stats []ChannelStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	for _, s := range stats {
+		if s.Name == "admin-stats" {
+			if s.Sent != 2 || s.Dropped != 1 || s.Buffered != 1 || s.BufferSize != 1 {
+				t.Errorf("Unexpected stats: %+v", s)
+			}
+			return
+		}
+	}
+	t.Errorf("admin-stats channel not listed: %s", rec.Body.String())
+}
+
+func TestAdminTail(t *testing.T) {
+	for _, format := range []string{"ndjson", "sse"} {
+		t.Run(format, func(t *testing.T) {
+			server := httptest.NewServer(NewAdminHandler())
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/tail?level=warn&format=" + format)
+			if err != nil {
+				t.Fatalf("GET /tail failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			lines := make(chan string)
+			go func() {
+				scanner := bufio.NewScanner(resp.Body)
+				for scanner.Scan() {
+					line := scanner.Text()
+					if format == "sse" {
+						if !strings.HasPrefix(line, "data: ") {
+							continue
+						}
+						line = strings.TrimPrefix(line, "data: ")
+					}
+					lines <- line
+				}
+				close(lines)
+			}()
+
+			Info("tail info")
+			Warn("tail warn %s", format)
+
+			select {
+			case line := <-lines:
+				var entry LogEntry
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("Invalid entry %q: %v", line, err)
+				}
+				if entry.Level != LevelWarn || entry.Message != "tail warn "+format {
+					t.Errorf("Unexpected entry: %+v", entry)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Timeout waiting for tail entry")
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"testing"
+)
+
+// AllocsPerRun 的结果在 race 检测器或覆盖率插桩下不可靠，这些测试只在普通构建下运行
+
+func TestDisabledLevelDoesNotAllocate(t *testing.T) {
+	if raceEnabled || testing.CoverMode() != "" {
+		t.Skip("allocation counts are not meaningful with -race or -cover")
+	}
+	withDiscardOutput(t)
+	SetLevel(LevelWarn)
+	defer SetLevel(LevelDebug)
+
+	n := 4096
+	named := Named("alloc-test").With(String("component", "api"))
+	cases := map[string]func(){
+		"Debug":        func() { Debug("cache miss for %s", "key") },
+		"Trace":        func() { Trace("frame %d", 7) },
+		"Info":         func() { Info("request handled") },
+		"Debugw":       func() { Debugw("cache miss", String("key", "k"), Int("shard", n)) },
+		"Named.Debug":  func() { named.Debug("cache miss for %s", "key") },
+		"Named.Infow":  func() { named.Infow("handled", Int("status", n), Duration("took", 1)) },
+		"Log(custom)":  func() { Log(LevelDebug, "custom %s", "level") },
+		"Tracew(none)": func() { Tracew("no fields") },
+	}
+
+	for name, fn := range cases {
+		if allocs := testing.AllocsPerRun(100, fn); allocs != 0 {
+			t.Errorf("%s allocated %.1f times per call on the disabled path", name, allocs)
+		}
+	}
+}
+
+func TestEnabledTextPathDoesNotAllocate(t *testing.T) {
+	if raceEnabled || testing.CoverMode() != "" {
+		t.Skip("allocation counts are not meaningful with -race or -cover")
+	}
+	withDiscardOutput(t)
+
+	n := 4096
+	cases := map[string]func(){
+		"Info":  func() { Info("request handled in %s", "12ms") },
+		"Infow": func() { Infow("request handled", String("path", "/api"), Int("status", n)) },
+		"Warn":  func() { Named("alloc-test").Warn("slow request") },
+	}
+
+	for name, fn := range cases {
+		if allocs := testing.AllocsPerRun(100, fn); allocs != 0 {
+			t.Errorf("%s allocated %.1f times per call on the text-only path", name, allocs)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+// withDiscardOutput 将输出重定向到 io.Discard，并在测试结束后恢复
+func withDiscardOutput(tb testing.TB) {
+	var console io.Writer
+	updateConfig(func(c *runtimeConfig) error {
+		console = c.console
+		c.console = io.Discard
+		c.custom = io.Discard
+		c.rebuildOutput()
+		return nil
+	})
+
+	tb.Cleanup(func() {
+		updateConfig(func(c *runtimeConfig) error {
+			c.console = console
+			c.custom = nil
+			c.rebuildOutput()
+			return nil
+		})
+	})
+}
+
+func BenchmarkInfo(b *testing.B) {
+	withDiscardOutput(b)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		Info("request handled in %s", "12ms")
+	}
+}
+
+func BenchmarkInfowTypedFields(b *testing.B) {
+	withDiscardOutput(b)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		Infow("request handled", String("path", "/api"), Int("status", 200+i%300), Duration("took", 12))
+	}
+}
+
+func BenchmarkDisabledDebug(b *testing.B) {
+	withDiscardOutput(b)
+	SetLevel(LevelInfo)
+	defer SetLevel(LevelDebug)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		Debug("cache miss for %s", "key")
+	}
+}
+
+func BenchmarkDisabledDebugwTypedFields(b *testing.B) {
+	withDiscardOutput(b)
+	SetLevel(LevelInfo)
+	defer SetLevel(LevelDebug)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		Debugw("cache miss", String("key", "k"), Int("shard", i))
+	}
+}
+
+func BenchmarkInfoWithChannel(b *testing.B) {
+	withDiscardOutput(b)
+	ch := GetLogChannelWithConfig("bench", LogChannelConfig{BufferSize: 1024})
+	defer RemoveLogChannel("bench")
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		Info("request handled in %s", "12ms")
+		<-ch
+	}
+}
+
+func BenchmarkLevels(b *testing.B) {
+	withDiscardOutput(b)
+	SetLevel(LevelTrace)
+	defer SetLevel(LevelDebug)
+
+	// 直接调用而不是通过函数值，否则可变参数切片会逃逸到堆上
+	funcs := []struct {
+		name string
+		log  func()
+	}{
+		{LevelTrace, func() { Trace("request handled in %s", "12ms") }},
+		{LevelDebug, func() { Debug("request handled in %s", "12ms") }},
+		{LevelInfo, func() { Info("request handled in %s", "12ms") }},
+		{LevelWarn, func() { Warn("request handled in %s", "12ms") }},
+		{LevelError, func() { Error("request handled in %s", "12ms") }}, // 每次打印堆栈
+	}
+
+	for _, fn := range funcs {
+		b.Run(fn.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				fn.log()
+			}
+		})
+		b.Run(fn.name+"/channel", func(b *testing.B) {
+			// 缓冲区满后每次发送都会丢弃最旧条目，覆盖 drop-oldest 路径
+			GetLogChannelWithConfig("bench-level", LogChannelConfig{BufferSize: 1})
+			defer RemoveLogChannel("bench-level")
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				fn.log()
+			}
+		})
+	}
+}
+
+func BenchmarkNamedLogger(b *testing.B) {
+	withDiscardOutput(b)
+	l := Named("bench").With(String("component", "api"))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		l.Info("request handled in %s", "12ms")
+	}
+}
+
+func BenchmarkReaderCopy(b *testing.B) {
+	withDiscardOutput(b)
+	reader, err := GetReaderCopy()
+	if err != nil {
+		b.Fatalf("GetReaderCopy failed: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, reader)
+		close(done)
+	}()
+	defer func() {
+		RemoveReaderCopy()
+		<-done
+	}()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		Info("request handled in %s", "12ms")
+	}
+}
+
+func BenchmarkParallel(b *testing.B) {
+	withDiscardOutput(b)
+
+	b.Run("text", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				Infow("request handled", Int("status", 200))
+			}
+		})
+	})
+
+	b.Run("disabled", func(b *testing.B) {
+		SetLevel(LevelInfo)
+		defer SetLevel(LevelDebug)
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				Debugw("cache miss", Int("shard", 1))
+			}
+		})
+	})
+
+	b.Run("channel", func(b *testing.B) {
+		GetLogChannelWithConfig("bench-parallel", LogChannelConfig{BufferSize: 64})
+		defer RemoveLogChannel("bench-parallel")
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				Infow("request handled", Int("status", 200))
+			}
+		})
+	})
+}
+
+func BenchmarkManyChannels(b *testing.B) {
+	withDiscardOutput(b)
+
+	for _, n := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			for i := 0; i < n; i++ {
+				GetLogChannelWithConfig(fmt.Sprintf("bench-many-%d", i), LogChannelConfig{BufferSize: 16})
+			}
+			defer func() {
+				for i := 0; i < n; i++ {
+					RemoveLogChannel(fmt.Sprintf("bench-many-%d", i))
+				}
+			}()
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				Info("request handled in %s", "12ms")
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"runtime"
+	"sync"
+)
+
+// maxPooledBuffer 是放回池中的缓冲区容量上限，避免偶发的大日志长期占用内存
+const maxPooledBuffer = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// getBuffer 从池中取出一个空缓冲区
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+// putBuffer 将缓冲区放回池中
+func putBuffer(b *[]byte) {
+	if cap(*b) > maxPooledBuffer {
+		return
+	}
+	*b = (*b)[:0]
+	bufferPool.Put(b)
+}
+
+// appendStack 追加当前 goroutine 的堆栈，内容与 debug.Stack 相同，
+// 但复用缓冲区的容量而不是每次分配
+func appendStack(buf []byte) []byte {
+	start := len(buf)
+	for size := 4096; ; size *= 2 {
+		if cap(buf)-start < size {
+			grown := make([]byte, start, start+size)
+			copy(grown, buf)
+			buf = grown
+		}
+		n := runtime.Stack(buf[start:start+size], false)
+		if n < size {
+			return buf[:start+n]
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CaptureConfig 配置标准输出和标准错误的捕获
+type CaptureConfig struct {
+	// Name 是条目所属命名 logger 的名称，作为输出中的子前缀
+	Name string
+	// StdoutLevel 默认 INFO，StderrLevel 默认 WARN
+	StdoutLevel string
+	StderrLevel string
+}
+
+func (c CaptureConfig) withDefaults(name string) CaptureConfig {
+	if c.Name == "" {
+		c.Name = name
+	}
+	if c.StdoutLevel == "" {
+		c.StdoutLevel = LevelInfo
+	}
+	if c.StderrLevel == "" {
+		c.StderrLevel = LevelWarn
+	}
+	return c
+}
+
+// CaptureCommand replaces cmd.Stdout and cmd.Stderr with writers that log
+// each line through the named logger config.Name, which defaults to the
+// base name of cmd.Path. Call it before cmd.Start and close the returned
+// io.Closer after cmd.Wait to log a final line without a newline.
+func CaptureCommand(cmd *exec.Cmd, config CaptureConfig) io.Closer {
+	config = config.withDefaults(filepath.Base(cmd.Path))
+
+	l := Named(config.Name)
+	stdout := l.Writer(config.StdoutLevel)
+	stderr := l.Writer(config.StderrLevel)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return multiCloser{stdout, stderr}
+}
+
+// RunCommand runs cmd with its output captured as in CaptureCommand
+func RunCommand(cmd *exec.Cmd, config CaptureConfig) error {
+	c := CaptureCommand(cmd, config)
+	err := cmd.Run()
+	c.Close()
+	return err
+}
+
+// multiCloser 依次关闭所有 Closer，返回第一个错误
+type multiCloser []io.Closer
+
+func (m multiCloser) Close() error {
+	var first error
+	for _, c := range m {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// stdioDrainTimeout 是恢复标准输出时等待管道读完的最长时间，
+// 继承了文件描述符的子进程可能使管道一直保持打开
+const stdioDrainTimeout = time.Second
+
+var (
+	stdioMu     sync.Mutex
+	stdioActive bool
+)
+
+// RedirectStdio redirects the process's own file descriptors 1 and 2 into
+// the logger: everything written to os.Stdout or os.Stderr, including by
+// C code and child processes that inherit them, is logged line by line
+// through the named logger config.Name (default "stdio").
+//
+// To avoid loops the console output, console outputs of configuration
+// files (also when applied later) and internal warnings of this package
+// write to duplicates of the original descriptors while the redirection is
+// active. Sinks created with os.Stdout or os.Stderr directly would feed
+// back into the logger and must not be used. Output written immediately
+// before the process exits, for example a panic trace, may be lost.
+//
+// The returned function restores the original descriptors. Only one
+// redirection can be active; it is not supported on Windows and plan9.
+func RedirectStdio(config CaptureConfig) (restore func(), err error) {
+	config = config.withDefaults("stdio")
+
+	stdioMu.Lock()
+	defer stdioMu.Unlock()
+	if stdioActive {
+		return nil, fmt.Errorf("stdio is already redirected")
+	}
+
+	r := &stdioRedirect{}
+	if err := r.start(config); err != nil {
+		r.closeFiles()
+		return nil, err
+	}
+	stdioActive = true
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			stdioMu.Lock()
+			defer stdioMu.Unlock()
+			r.stop()
+			stdioActive = false
+		})
+	}, nil
+}
+
+// stdioRedirect 保存重定向前的文件描述符和读取管道的 goroutine
+type stdioRedirect struct {
+	origStdout *os.File // fd 1 的副本
+	origStderr *os.File // fd 2 的副本
+	readers    []*os.File
+	wg         sync.WaitGroup
+}
+
+func (r *stdioRedirect) start(config CaptureConfig) error {
+	fd, err := dupFD(1)
+	if err != nil {
+		return fmt.Errorf("dup stdout: %w", err)
+	}
+	r.origStdout = os.NewFile(uintptr(fd), "/dev/stdout")
+	if fd, err = dupFD(2); err != nil {
+		return fmt.Errorf("dup stderr: %w", err)
+	}
+	r.origStderr = os.NewFile(uintptr(fd), "/dev/stderr")
+
+	l := Named(config.Name)
+	for _, target := range []struct {
+		fd    int
+		level string
+	}{{1, config.StdoutLevel}, {2, config.StderrLevel}} {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			r.restoreFDs()
+			return err
+		}
+		r.readers = append(r.readers, reader)
+		err = redirectFD(int(writer.Fd()), target.fd)
+		// fd 1/2 已指向管道，关闭多余的写端，恢复后读端才能读到 EOF
+		writer.Close()
+		if err != nil {
+			r.restoreFDs()
+			return fmt.Errorf("redirect fd %d: %w", target.fd, err)
+		}
+
+		w := l.Writer(target.level)
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			io.Copy(w, reader)
+			w.Close()
+		}()
+	}
+
+	updateConfig(func(c *runtimeConfig) error {
+		if c.console == c.stdout {
+			c.console = r.origStdout
+			c.rebuildOutput()
+		}
+		c.stdout = r.origStdout
+		c.stderr = r.origStderr
+		return nil
+	})
+	return nil
+}
+
+func (r *stdioRedirect) stop() {
+	updateConfig(func(c *runtimeConfig) error {
+		if c.console == r.origStdout {
+			c.console = os.Stdout
+			c.rebuildOutput()
+		}
+		c.stdout = os.Stdout
+		c.stderr = os.Stderr
+		return nil
+	})
+	r.restoreFDs()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(stdioDrainTimeout):
+	}
+	r.closeFiles()
+}
+
+// restoreFDs 将 fd 1/2 指回原来的文件
+func (r *stdioRedirect) restoreFDs() {
+	if r.origStdout != nil {
+		redirectFD(int(r.origStdout.Fd()), 1)
+	}
+	if r.origStderr != nil {
+		redirectFD(int(r.origStderr.Fd()), 2)
+	}
+}
+
+func (r *stdioRedirect) closeFiles() {
+	for _, reader := range r.readers {
+		reader.Close()
+	}
+	if r.origStdout != nil {
+		r.origStdout.Close()
+	}
+	if r.origStderr != nil {
+		r.origStderr.Close()
+	}
+}
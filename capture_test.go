@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// 作为子进程运行时输出固定内容，供 CaptureCommand 测试使用
+func TestCaptureHelperProcess(t *testing.T) {
+	if os.Getenv("LOGGER_CAPTURE_HELPER") != "1" {
+		return
+	}
+	fmt.Fprintln(os.Stdout, "to stdout")
+	fmt.Fprintln(os.Stderr, "to stderr")
+	fmt.Fprint(os.Stdout, "no newline")
+	os.Exit(0)
+}
+
+func helperCommand() *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCaptureHelperProcess$")
+	cmd.Env = append(os.Environ(), "LOGGER_CAPTURE_HELPER=1")
+	return cmd
+}
+
+func TestRunCommand(t *testing.T) {
+	sink := captureEntries(t)
+
+	err := RunCommand(helperCommand(), CaptureConfig{Name: "child", StderrLevel: LevelError})
+	if err != nil {
+		t.Fatalf("RunCommand: %v", err)
+	}
+
+	want := map[string]string{"to stdout": LevelInfo, "to stderr": LevelError, "no newline": LevelInfo}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(sink.entries), len(want), sink.entries)
+	}
+	for _, entry := range sink.entries {
+		if entry.Logger != "child" || want[entry.Message] != entry.Level {
+			t.Errorf("unexpected entry [%s] [%s] %s", entry.Level, entry.Logger, entry.Message)
+		}
+	}
+}
+
+func TestCaptureCommandDefaultName(t *testing.T) {
+	cmd := helperCommand()
+	c := CaptureCommand(cmd, CaptureConfig{})
+	defer c.Close()
+
+	if cmd.Stdout == nil || cmd.Stderr == nil {
+		t.Fatal("CaptureCommand did not set Stdout and Stderr")
+	}
+	found := false
+	for _, name := range LoggerNames() {
+		if name == filepath.Base(cmd.Path) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("no named logger for %s in %v", cmd.Path, LoggerNames())
+	}
+}
+
+func TestRedirectStdio(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("stdio redirection is not supported")
+	}
+	sink := captureEntries(t)
+
+	restore, err := RedirectStdio(CaptureConfig{})
+	if err != nil {
+		t.Fatalf("RedirectStdio: %v", err)
+	}
+	if _, err := RedirectStdio(CaptureConfig{}); err == nil {
+		t.Error("second RedirectStdio should fail")
+	}
+	if currentConfig().stderr == os.Stderr {
+		t.Error("internal warnings still go to the redirected stderr")
+	}
+
+	fmt.Println("printed to stdout")
+	fmt.Fprintln(os.Stderr, "printed to stderr")
+	restore()
+	restore()
+
+	if currentConfig().stderr != os.Stderr {
+		t.Error("restore did not reset the warning output")
+	}
+	got := strings.Join(sink.messages(), "|")
+	if got != "INFO printed to stdout|WARN printed to stderr" && got != "WARN printed to stderr|INFO printed to stdout" {
+		t.Errorf("entries = %q", got)
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	for _, entry := range sink.entries {
+		if entry.Logger != "stdio" {
+			t.Errorf("Logger = %q, want stdio", entry.Logger)
+		}
+	}
+}
+
+func TestRedirectStdioSurvivesApply(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+		t.Skip("stdio redirection is not supported")
+	}
+	sink := captureEntries(t)
+	resetFileConfig(t)
+
+	restore, err := RedirectStdio(CaptureConfig{})
+	if err != nil {
+		t.Fatalf("RedirectStdio: %v", err)
+	}
+	defer restore()
+
+	config, err := ParseConfig([]byte(`{"outputs": [
+		{"type": "console"},
+		{"name": "json", "type": "console", "format": "json", "stream": "stderr"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	c := currentConfig()
+	if c.console == os.Stdout || c.console != c.stdout {
+		t.Error("Apply pointed the console at the redirected stdout")
+	}
+
+	Info("logged once")
+	restore()
+
+	// 控制台和 JSON 输出写回管道时条目会被再次记录
+	if got := sink.messages(); len(got) != 1 || got[0] != "INFO logged once" {
+		t.Errorf("entries = %q", got)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"repo.smlk.org/logger"
+)
+
+// followFiles 输出各文件现有的条目，然后每隔 interval 输出新写入的条目，直到 ctx 取消
+func followFiles(ctx context.Context, files []string, p *printer, interval time.Duration) error {
+	followers := make([]*follower, 0, len(files))
+	defer func() {
+		for _, f := range followers {
+			f.close()
+		}
+	}()
+
+	for _, path := range files {
+		f := &follower{path: path}
+		if err := f.open(); err != nil {
+			return err
+		}
+		followers = append(followers, f)
+		if err := readEntries(f.reader, p); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	emit := func(entry logger.LogEntry) error {
+		if !p.opts.match(entry) {
+			return nil
+		}
+		return p.print(entry)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		for _, f := range followers {
+			if err := f.poll(emit); err != nil {
+				return fmt.Errorf("%s: %w", f.path, err)
+			}
+		}
+	}
+}
+
+// follower 跟踪一个路径，路径指向的文件被改名后重新创建或被截断时从头读取
+type follower struct {
+	path   string
+	file   *os.File // 文件已改名而新文件尚未创建时为 nil
+	info   os.FileInfo
+	lines  *lineReader
+	reader *logger.EntryReader
+}
+
+func (f *follower) open() error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.info = file, info
+	f.reset()
+	return nil
+}
+
+// reset 从文件当前位置重新开始解析
+func (f *follower) reset() {
+	f.lines = &lineReader{r: f.file}
+	f.reader = logger.NewEntryReader(f.lines)
+}
+
+func (f *follower) close() {
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+}
+
+// read 输出当前可读的全部完整条目
+func (f *follower) read(emit func(logger.LogEntry) error) error {
+	for f.reader.Next() {
+		if err := emit(f.reader.Entry()); err != nil {
+			return err
+		}
+	}
+	return f.reader.Err()
+}
+
+// poll 输出新条目，并检查路径是否已指向新文件或文件是否被截断
+func (f *follower) poll(emit func(logger.LogEntry) error) error {
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+	}
+	if err := f.read(emit); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(f.path)
+	if os.IsNotExist(err) {
+		// 轮转时旧文件已改名而新文件尚未创建，继续读旧文件
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !os.SameFile(f.info, info):
+		// 读完旧文件，包括没有换行结尾的最后一行，再从头读新文件
+		if err := f.read(emit); err != nil {
+			return err
+		}
+		if err := emitAll(bytes.NewReader(f.lines.partial()), emit); err != nil {
+			return err
+		}
+		f.close()
+		if err := f.open(); err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		return f.read(emit)
+	case info.Size() < f.lines.offset:
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		f.reset()
+		return f.read(emit)
+	}
+	return nil
+}
+
+// emitAll 输出 r 中的全部条目
+func emitAll(r io.Reader, emit func(logger.LogEntry) error) error {
+	reader := logger.NewEntryReader(r)
+	for reader.Next() {
+		if err := emit(reader.Entry()); err != nil {
+			return err
+		}
+	}
+	return reader.Err()
+}
+
+// lineReader 只返回以换行结尾的完整行，正在写入的半行留到写完后再返回
+type lineReader struct {
+	r      io.Reader
+	buf    []byte // 已读取、尚未返回的数据
+	ready  int    // buf 中到最后一个换行为止的长度
+	offset int64  // 从 r 读取的总字节数
+	chunk  [32 << 10]byte
+}
+
+func (l *lineReader) Read(p []byte) (int, error) {
+	for l.ready == 0 {
+		n, err := l.r.Read(l.chunk[:])
+		l.offset += int64(n)
+		l.buf = append(l.buf, l.chunk[:n]...)
+		if i := bytes.LastIndexByte(l.buf, '\n'); i >= 0 {
+			l.ready = i + 1
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, l.buf[:l.ready])
+	l.buf = append(l.buf[:0], l.buf[n:]...)
+	l.ready -= n
+	return n, nil
+}
+
+// partial 返回没有换行结尾的剩余数据
+func (l *lineReader) partial() []byte {
+	return l.buf[l.ready:]
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"repo.smlk.org/logger"
+)
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func textLine(message string) string {
+	return "2024/05/06 07:08:09 [INFO] " + message + "\n"
+}
+
+// pollMessages 轮询一次并返回新条目的消息
+func pollMessages(t *testing.T, f *follower) []string {
+	t.Helper()
+	var messages []string
+	err := f.poll(func(entry logger.LogEntry) error {
+		messages = append(messages, entry.Message)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	return messages
+}
+
+func expectMessages(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("messages = %q, want %q", got, want)
+	}
+}
+
+func TestFollowerPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, textLine("one")+"2024/05/06 07:08:09 [INFO] tw")
+
+	f := &follower{path: path}
+	if err := f.open(); err != nil {
+		t.Fatal(err)
+	}
+	defer f.close()
+
+	expectMessages(t, pollMessages(t, f), "one")
+	appendFile(t, path, "o\n")
+	expectMessages(t, pollMessages(t, f), "two")
+}
+
+func TestFollowerRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, textLine("old"))
+
+	f := &follower{path: path}
+	if err := f.open(); err != nil {
+		t.Fatal(err)
+	}
+	defer f.close()
+	expectMessages(t, pollMessages(t, f), "old")
+
+	// 改名后新文件尚未创建时继续读旧文件
+	appendFile(t, path, textLine("late")+"2024/05/06 07:08:09 [INFO] unterminated")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	expectMessages(t, pollMessages(t, f), "late")
+
+	appendFile(t, path, textLine("new"))
+	expectMessages(t, pollMessages(t, f), "unterminated", "new")
+
+	// 截断后从头读；截断后的长度超过已读位置前需要轮询到一次
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	expectMessages(t, pollMessages(t, f))
+	appendFile(t, path, textLine("again"))
+	expectMessages(t, pollMessages(t, f), "again")
+}
+
+// syncBuffer 可在 followFiles 写入的同时读取
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestFollowFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, textLine("first")+textLine("second")+textLine("third"))
+
+	out := &syncBuffer{}
+	p := &printer{w: out, opts: &options{format: "text", tail: 1}}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- followFiles(ctx, []string{path}, p, 10*time.Millisecond) }()
+
+	want := textLine("third") + textLine("fourth")
+	appended := false
+	deadline := time.Now().Add(5 * time.Second)
+	for out.String() != want && time.Now().Before(deadline) {
+		if !appended && out.String() == textLine("third") {
+			appendFile(t, path, textLine("fourth"))
+			appended = true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("followFiles: %v", err)
+	}
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
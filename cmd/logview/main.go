@@ -0,0 +1,309 @@
+// Command logview reads and follows log files written by repo.smlk.org/logger.
+//
+// It parses both the text format of the console, TextFormatter and
+// RotatingFile outputs and the JSON lines of JSONFormatter, filters the
+// entries, and prints them with colored levels and folded stack traces or
+// converts them to the other format.
+//
+// Usage:
+//
+//	logview [flags] [file ...]
+//
+// With no files, or with "-", standard input is read. Examples:
+//
+//	logview -level warn -since 1h app.log
+//	logview -f -grep 'timeout|refused' /var/log/app.log
+//	logview -o json -prefix MyApp app.log > app.jsonl
+//
+// Flags:
+//
+//	-level L     show entries at level L or above
+//	-prefix P    show entries whose product prefix is P
+//	-logger N    show entries of the named logger N
+//	-grep RE     show entries whose message matches the regular expression RE
+//	-since T     show entries at or after T
+//	-until T     show entries at or before T
+//	-n N         show only the last N matching entries of each file
+//	-f           follow files as they grow, across renames and truncation
+//	-o FORMAT    output format: text (default) or json
+//	-color WHEN  color levels: auto (default), always or never
+//	-fold        fold stack traces to the first application frame (default true)
+//
+// Times are RFC 3339, "2006/01/02 15:04:05" or "2006-01-02 15:04:05" in
+// local time, a date, or a duration such as 30m meaning that long ago.
+//
+// With -f, files are polled for new complete lines. When the path is renamed
+// and recreated, as RotatingFile and logrotate do, the rest of the old file
+// is printed and the new file is read from the start. A file truncated in
+// place is read from the start once it is seen shorter than before.
+//
+// Text lines carry no separate fields, so fields stay part of the message
+// when converting text to JSON.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"time"
+
+	"repo.smlk.org/logger"
+)
+
+// followInterval 是 -f 模式下检查文件变化的间隔
+const followInterval = 250 * time.Millisecond
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// options 保存解析后的命令行参数
+type options struct {
+	filter logger.EntryFilter
+	grep   *regexp.Regexp
+	since  time.Time
+	until  time.Time
+	tail   int
+	follow bool
+	format string
+	color  bool
+	fold   bool
+	files  []string
+}
+
+// match 判断条目是否满足所有过滤条件
+func (o *options) match(entry logger.LogEntry) bool {
+	if !o.filter.Match(entry) {
+		return false
+	}
+	if !o.since.IsZero() && entry.Timestamp.Before(o.since) {
+		return false
+	}
+	if !o.until.IsZero() && entry.Timestamp.After(o.until) {
+		return false
+	}
+	return o.grep == nil || o.grep.MatchString(entry.Message)
+}
+
+// run 执行命令并返回退出码，ctx 取消时结束 -f 模式
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts, err := parseFlags(args, stdout, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "logview:", err)
+		return 2
+	}
+
+	p := &printer{w: stdout, opts: opts}
+	if opts.follow {
+		err = followFiles(ctx, opts.files, p, followInterval)
+	} else {
+		err = readFiles(opts.files, stdin, p)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "logview:", err)
+		return 1
+	}
+	return 0
+}
+
+func parseFlags(args []string, stdout, stderr io.Writer) (*options, error) {
+	fs := flag.NewFlagSet("logview", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: logview [flags] [file ...]")
+		fs.PrintDefaults()
+	}
+
+	opts := &options{}
+	level := fs.String("level", "", "show entries at `level` or above")
+	fs.StringVar(&opts.filter.Prefix, "prefix", "", "show entries with this product `prefix`")
+	fs.StringVar(&opts.filter.Logger, "logger", "", "show entries of the named logger `name`")
+	grep := fs.String("grep", "", "show entries whose message matches the `regexp`")
+	since := fs.String("since", "", "show entries at or after `time`")
+	until := fs.String("until", "", "show entries at or before `time`")
+	fs.IntVar(&opts.tail, "n", 0, "show only the last `N` matching entries of each file")
+	fs.BoolVar(&opts.follow, "f", false, "follow files as they grow")
+	fs.StringVar(&opts.format, "o", "text", "output `format`: text or json")
+	color := fs.String("color", "auto", "color levels: auto, always or never")
+	fs.BoolVar(&opts.fold, "fold", true, "fold stack traces")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	opts.files = fs.Args()
+
+	if *level != "" {
+		spec, ok := logger.GetLevelSpec(*level)
+		if !ok {
+			return nil, fmt.Errorf("unknown level %q", *level)
+		}
+		opts.filter.Level = spec.Name
+	}
+	if *grep != "" {
+		re, err := regexp.Compile(*grep)
+		if err != nil {
+			return nil, fmt.Errorf("-grep: %w", err)
+		}
+		opts.grep = re
+	}
+
+	now := time.Now()
+	var err error
+	if opts.since, err = parseTime(*since, now); err != nil {
+		return nil, fmt.Errorf("-since: %w", err)
+	}
+	if opts.until, err = parseTime(*until, now); err != nil {
+		return nil, fmt.Errorf("-until: %w", err)
+	}
+	if opts.tail < 0 {
+		return nil, fmt.Errorf("-n must not be negative")
+	}
+
+	switch opts.format {
+	case "text", "json":
+	default:
+		return nil, fmt.Errorf("unknown output format %q", opts.format)
+	}
+
+	switch *color {
+	case "always":
+		opts.color = true
+	case "never":
+	case "auto":
+		opts.color = isTerminal(stdout) && os.Getenv("NO_COLOR") == ""
+	default:
+		return nil, fmt.Errorf("invalid -color %q", *color)
+	}
+
+	if opts.follow {
+		if len(opts.files) == 0 {
+			return nil, fmt.Errorf("-f needs at least one file")
+		}
+		for _, name := range opts.files {
+			if name == "-" {
+				return nil, fmt.Errorf("-f cannot follow standard input")
+			}
+		}
+	}
+	return opts, nil
+}
+
+// parseTime 解析时间参数，空字符串返回零值；时长表示距 now 多久之前
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{logger.TextTimeLayout, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// readFiles 依次读取所有文件，没有文件或文件名为 "-" 时读取 stdin
+func readFiles(files []string, stdin io.Reader, p *printer) error {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		if name == "-" {
+			if err := readEntries(logger.NewEntryReader(stdin), p); err != nil {
+				return fmt.Errorf("stdin: %w", err)
+			}
+			continue
+		}
+
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = readEntries(logger.NewEntryReader(f), p)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// readEntries 读取 reader 中当前的全部条目，按 -n 只输出最后若干条
+func readEntries(reader *logger.EntryReader, p *printer) error {
+	tail := newTail(p.opts.tail)
+	for reader.Next() {
+		entry := reader.Entry()
+		if !p.opts.match(entry) {
+			continue
+		}
+		if tail != nil {
+			tail.add(entry)
+			continue
+		}
+		if err := p.print(entry); err != nil {
+			return err
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return err
+	}
+	return tail.flush(p)
+}
+
+// tailBuffer 保留最后 size 条条目
+type tailBuffer struct {
+	entries []logger.LogEntry
+	size    int
+}
+
+// newTail 在 size 为 0 时返回 nil，表示不限制
+func newTail(size int) *tailBuffer {
+	if size == 0 {
+		return nil
+	}
+	return &tailBuffer{size: size}
+}
+
+func (t *tailBuffer) add(entry logger.LogEntry) {
+	if len(t.entries) == t.size {
+		copy(t.entries, t.entries[1:])
+		t.entries = t.entries[:t.size-1]
+	}
+	t.entries = append(t.entries, entry)
+}
+
+func (t *tailBuffer) flush(p *printer) error {
+	if t == nil {
+		return nil
+	}
+	for _, entry := range t.entries {
+		if err := p.print(entry); err != nil {
+			return err
+		}
+	}
+	t.entries = nil
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"repo.smlk.org/logger"
+)
+
+const exampleLog = "../../examples/example.log"
+
+// logview 以 args 运行并返回标准输出
+func logview(t *testing.T, stdin string, args ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr); code != 0 {
+		t.Fatalf("logview %v exited with %d: %s", args, code, stderr.String())
+	}
+	return stdout.String()
+}
+
+func TestFilters(t *testing.T) {
+	input := "2024/05/06 07:00:00 [svc] [INFO] [api] started\n" +
+		"2024/05/06 08:00:00 [svc] [WARN] [db] slow query\n" +
+		"2024/05/06 09:00:00 [other] [ERROR] connection refused\n" +
+		"2024/05/06 10:00:00 [svc] [DEBUG] [api] request timeout\n"
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"-level", "warn"}, []string{"slow query", "connection refused"}},
+		{[]string{"-prefix", "svc", "-logger", "api"}, []string{"started", "request timeout"}},
+		{[]string{"-grep", "refused|timeout"}, []string{"connection refused", "request timeout"}},
+		{[]string{"-since", "2024/05/06 08:00:00", "-until", "2024-05-06 09:00:00"}, []string{"slow query", "connection refused"}},
+		{[]string{"-n", "2", "-level", "info"}, []string{"slow query", "connection refused"}},
+	}
+	for _, tt := range tests {
+		got := logview(t, input, append([]string{"-color", "never"}, tt.args...)...)
+		lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+		if len(lines) != len(tt.want) {
+			t.Errorf("%v: got %q, want messages %q", tt.args, got, tt.want)
+			continue
+		}
+		for i, line := range lines {
+			if !strings.HasSuffix(line, " "+tt.want[i]) {
+				t.Errorf("%v: line %d = %q, want message %q", tt.args, i, line, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestFlagErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"-level", "loud"},
+		{"-grep", "("},
+		{"-since", "yesterday"},
+		{"-o", "xml"},
+		{"-color", "sometimes"},
+		{"-f"},
+		{"-f", "-"},
+		{"-n", "-1"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(context.Background(), args, strings.NewReader(""), &stdout, &stderr); code != 2 {
+			t.Errorf("%v: exit code %d, want 2", args, code)
+		}
+	}
+}
+
+func TestExampleLogFoldedAndColored(t *testing.T) {
+	got := logview(t, "", "-color", "always", "-level", "error", exampleLog)
+	want := "2026/02/12 18:28:00 [ExampleApp] \x1b[31m[ERROR]\x1b[0m Database connection timeout\n" +
+		"\x1b[90mgoroutine 1 [running]:\n" +
+		"main.main()\n" +
+		"\tC:/Users/inku33/Desktop/golang/logger/examples/main.go:54 +0x2ac\n" +
+		"\t... 4 more lines\x1b[0m\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	unfolded := logview(t, "", "-color", "never", "-fold=false", "-level", "error", exampleLog)
+	if !strings.Contains(unfolded, "runtime/debug.Stack()\n") || strings.Contains(unfolded, "more lines") {
+		t.Errorf("-fold=false output:\n%s", unfolded)
+	}
+}
+
+func TestConvertTextAndJSON(t *testing.T) {
+	jsonl := logview(t, "", "-o", "json", exampleLog)
+	text := logview(t, jsonl, "-color", "never", "-fold=false")
+
+	original := logview(t, "", "-color", "never", "-fold=false", exampleLog)
+	if text != original {
+		t.Errorf("text -> json -> text changed the output:\n%s\nwant:\n%s", text, original)
+	}
+	if back := logview(t, text, "-o", "json"); back != jsonl {
+		t.Errorf("json round trip changed the output:\n%s\nwant:\n%s", back, jsonl)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.Local)
+	tests := map[string]time.Time{
+		"":                          {},
+		"90m":                       now.Add(-90 * time.Minute),
+		"2024-05-06T10:00:00Z":      time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC),
+		"2024/05/06 10:00:00":       time.Date(2024, 5, 6, 10, 0, 0, 0, time.Local),
+		"2024-05-06":                time.Date(2024, 5, 6, 0, 0, 0, 0, time.Local),
+		"2024-05-06 10:00:00":       time.Date(2024, 5, 6, 10, 0, 0, 0, time.Local),
+		"2024-05-06T10:00:00+02:00": time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC),
+	}
+	for input, want := range tests {
+		got, err := parseTime(input, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseTime(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+}
+
+func TestFoldStack(t *testing.T) {
+	stack := "goroutine 7 [running]:\n" +
+		"runtime/debug.Stack()\n\t/go/src/runtime/debug/stack.go:26 +0x5e\n" +
+		"repo.smlk.org/logger.(*Logger).log(0xc000010000, {0x0, 0x0})\n\t/src/logger/logger.go:200 +0x1\n" +
+		"repo.smlk.org/logger.Error(...)\n\t/src/logger/logger.go:114\n" +
+		"example.com/app/db.Open({0x1, 0x2})\n\t/src/app/db/open.go:12 +0x3\n" +
+		"main.main()\n\t/src/app/main.go:5 +0x4\n"
+
+	want := "goroutine 7 [running]:\n" +
+		"example.com/app/db.Open({0x1, 0x2})\n\t/src/app/db/open.go:12 +0x3\n" +
+		"\t... 8 more lines\n"
+	if got := foldStack(stack); got != want {
+		t.Errorf("foldStack:\n%s\nwant:\n%s", got, want)
+	}
+
+	// 不是 goroutine 堆栈时保留前三行
+	if got := foldStack("a\nb\nc\nd\n"); got != "a\nb\nc\n\t... 1 more lines\n" {
+		t.Errorf("foldStack(other) = %q", got)
+	}
+}
+
+func TestCustomLevelStack(t *testing.T) {
+	entry := logger.LogEntry{
+		Timestamp:  time.Date(2024, 5, 6, 7, 8, 9, 0, time.Local),
+		Level:      "PANIC2",
+		Message:    "custom",
+		StackTrace: []byte("goroutine 1 [running]:\nmain.main()\n"),
+	}
+	data, _ := logger.TextFormatter{}.Format(entry)
+
+	got := logview(t, string(data)+string(entry.StackTrace), "-color", "never")
+	if !strings.HasSuffix(got, "[PANIC2] custom\ngoroutine 1 [running]:\nmain.main()\n") {
+		t.Errorf("unregistered level lost its stack trace:\n%s", got)
+	}
+}
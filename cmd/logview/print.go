@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"repo.smlk.org/logger"
+)
+
+// ANSI 颜色序列，与 logger 包的控制台输出一致
+const (
+	colorReset = "\x1b[0m"
+	colorGray  = "\x1b[90m"
+)
+
+// printer 按输出格式写出条目
+type printer struct {
+	w    io.Writer
+	opts *options
+}
+
+func (p *printer) print(entry logger.LogEntry) error {
+	var data []byte
+	var err error
+	if p.opts.format == "json" {
+		data, err = logger.JSONFormatter{}.Format(entry)
+	} else {
+		data, err = p.formatText(entry)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = p.w.Write(data)
+	return err
+}
+
+// formatText 按 TextFormatter 的格式输出，级别标签按级别着色，堆栈可折叠
+func (p *printer) formatText(entry logger.LogEntry) ([]byte, error) {
+	stack := entry.StackTrace
+	entry.StackTrace = nil
+	if entry.Timestamp.IsZero() {
+		// 首个条目之前的行没有时间戳，原样输出
+		return []byte(entry.Message + "\n"), nil
+	}
+	line, err := logger.TextFormatter{}.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, registered := logger.GetLevelSpec(entry.Level)
+	if p.opts.color && spec.Color != "" {
+		tag := len(logger.TextTimeLayout) + 1
+		if entry.Prefix != "" {
+			tag += len(entry.Prefix) + 3 // "[Prefix] "
+		}
+		end := tag + len(entry.Level) + 2 // "[LEVEL]"
+		colored := make([]byte, 0, len(line)+len(spec.Color)+len(colorReset))
+		colored = append(colored, line[:tag]...)
+		colored = append(colored, spec.Color...)
+		colored = append(colored, line[tag:end]...)
+		colored = append(colored, colorReset...)
+		line = append(colored, line[end:]...)
+	}
+
+	// 与 TextFormatter 一致只为打印堆栈的级别输出堆栈；
+	// 未注册的自定义级别无从判断，有堆栈就输出
+	if len(stack) == 0 || registered && spec.Stack != logger.StackPrint {
+		return line, nil
+	}
+	trace := string(stack)
+	if p.opts.fold {
+		trace = foldStack(trace)
+	}
+	if p.opts.color {
+		trace = colorGray + strings.TrimSuffix(trace, "\n") + colorReset + "\n"
+	}
+	return append(line, trace...), nil
+}
+
+// foldStack 只保留 goroutine 行和第一个不属于日志库的栈帧，其余行折叠为一行计数
+func foldStack(stack string) string {
+	lines := strings.Split(strings.TrimSuffix(stack, "\n"), "\n")
+	keep := len(lines)
+	if keep > 3 {
+		keep = 3
+	}
+	shown := lines[:keep]
+
+	if strings.HasPrefix(lines[0], "goroutine ") {
+		// 栈帧为函数行加一行缩进的位置
+		for i := 1; i < len(lines); i++ {
+			if strings.HasPrefix(lines[i], "\t") || isLibraryFrame(lines[i]) {
+				continue
+			}
+			shown = []string{lines[0], lines[i]}
+			if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+				shown = append(shown, lines[i+1])
+			}
+			break
+		}
+	}
+
+	var b strings.Builder
+	for _, line := range shown {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	if hidden := len(lines) - len(shown); hidden > 0 {
+		fmt.Fprintf(&b, "\t... %d more lines\n", hidden)
+	}
+	return b.String()
+}
+
+// isLibraryFrame 判断函数行是否属于 runtime/debug 或名为 logger 的包，
+// 即采集堆栈和写日志本身的栈帧
+func isLibraryFrame(line string) bool {
+	name := line
+	if i := strings.IndexByte(name, '('); i > 0 {
+		name = name[:i]
+	}
+	if strings.HasPrefix(name, "runtime/debug.") {
+		return true
+	}
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.HasPrefix(name, "logger.")
+}
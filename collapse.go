@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CollapseConfig 配置连续重复消息的折叠
+type CollapseConfig struct {
+	// Timeout 是重复计数的最长等待时间，到期后输出汇总，默认 30s
+	Timeout time.Duration
+	// Channels 为 true 时 channel 也只收到折叠后的条目，否则 channel 收到每一条
+	Channels bool
+}
+
+// collapser 保存最近一条已送达的消息及其后的重复次数
+type collapser struct {
+	config CollapseConfig
+
+	mu      sync.Mutex
+	last    collapseKey
+	spec    LevelSpec
+	entry   LogEntry
+	has     bool
+	repeats int
+	timer   *time.Timer
+	timerID uint64 // 区分已被替换的定时器
+}
+
+// collapseKey 标识相同的消息：级别、前缀、logger、格式字符串和输出内容均相同
+type collapseKey struct {
+	level  string
+	prefix string
+	logger string
+	format string
+	text   string
+}
+
+var (
+	activeCollapser atomic.Pointer[collapser]
+	collapseMutex   sync.Mutex // 串行化 SetCollapse
+)
+
+// SetCollapse collapses consecutive identical messages like syslogd: the
+// first one is written, repeats are counted, and a "last message repeated N
+// times" entry is written when a different message arrives or Timeout
+// expires. A nil config disables collapsing after writing the pending
+// summary.
+func SetCollapse(config *CollapseConfig) {
+	collapseMutex.Lock()
+	defer collapseMutex.Unlock()
+
+	var c *collapser
+	if config != nil {
+		c = &collapser{config: *config}
+		if c.config.Timeout <= 0 {
+			c.config.Timeout = 30 * time.Second
+		}
+	}
+
+	if old := activeCollapser.Swap(c); old != nil {
+		old.mu.Lock()
+		old.flushLocked()
+		old.has = false
+		old.mu.Unlock()
+	}
+}
+
+// submit 送达新消息，或在与上一条相同时只增加重复计数
+func (c *collapser) submit(spec LevelSpec, format string, entry LogEntry) {
+	if !c.config.Channels {
+		broadcastToChannels(entry)
+		// 不输出的条目不参与折叠，避免打断输出中的重复序列
+		if !outputEnabled(spec, entry.Logger) {
+			return
+		}
+	}
+
+	key := collapseKey{
+		level:  entry.Level,
+		prefix: entry.Prefix,
+		logger: entry.Logger,
+		format: format,
+		text:   entry.Message + formatFields(entry.Fields),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.has && key == c.last {
+		c.repeats++
+		if c.timer == nil {
+			c.timerID++
+			id := c.timerID
+			c.timer = time.AfterFunc(c.config.Timeout, func() { c.expire(id) })
+		}
+		return
+	}
+
+	c.flushLocked()
+	c.last, c.spec, c.entry, c.has = key, spec, entry, true
+	c.deliver(spec, entry)
+}
+
+// expire 在超时后输出汇总，之后的重复重新计数
+func (c *collapser) expire(id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.timer == nil || c.timerID != id {
+		return
+	}
+	c.timer = nil
+	c.flushLocked()
+}
+
+// flushLocked 输出待处理的重复汇总，调用方持有 c.mu
+func (c *collapser) flushLocked() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if c.repeats == 0 {
+		return
+	}
+
+	spec := c.spec
+	spec.Stack = StackNone
+
+	summary := c.entry
+	summary.Timestamp = time.Now()
+	summary.Message = fmt.Sprintf("last message repeated %d times", c.repeats)
+	summary.Fields = nil
+	summary.StackTrace = []byte{}
+	c.repeats = 0
+
+	c.deliver(spec, summary)
+}
+
+// deliver 按配置将条目送达 channel 和输出
+func (c *collapser) deliver(spec LevelSpec, entry LogEntry) {
+	if c.config.Channels {
+		broadcastToChannels(entry)
+	}
+	writeOutputs(spec, entry)
+}
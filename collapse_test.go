@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollapseConsecutiveDuplicates(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	ch := GetLogChannelWithConfig("collapse-test", LogChannelConfig{BufferSize: 100})
+	defer RemoveLogChannel("collapse-test")
+
+	SetCollapse(&CollapseConfig{Timeout: time.Hour})
+	for i := 0; i < 4; i++ {
+		Warn("disk %s almost full", "/var")
+	}
+	Warn("disk %s almost full", "/tmp")
+	Warn("disk %s almost full", "/tmp")
+	SetCollapse(nil)
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		lines = append(lines, line[strings.Index(line, "[WARN]"):])
+	}
+	want := []string{
+		"[WARN] disk /var almost full",
+		"[WARN] last message repeated 3 times",
+		"[WARN] disk /tmp almost full",
+		"[WARN] last message repeated 1 times",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected output:\n%s", strings.Join(lines, "\n"))
+	}
+
+	// 默认 channel 收到每一条
+	if len(ch) != 6 {
+		t.Errorf("Expected all 6 entries on channel, got %d", len(ch))
+	}
+}
+
+func TestCollapseChannels(t *testing.T) {
+	ch := GetLogChannelWithConfig("collapse-channels", LogChannelConfig{BufferSize: 100})
+	defer RemoveLogChannel("collapse-channels")
+
+	SetCollapse(&CollapseConfig{Timeout: time.Hour, Channels: true})
+	db := Named("collapse-db")
+	db.Info("retry")
+	db.Info("retry")
+	Info("retry") // 不同 logger，不属于重复
+	SetCollapse(nil)
+
+	var got []string
+	for len(ch) > 0 {
+		entry := <-ch
+		got = append(got, entry.Logger+":"+entry.Message)
+	}
+	want := "collapse-db:retry|collapse-db:last message repeated 1 times|:retry"
+	if strings.Join(got, "|") != want {
+		t.Errorf("Unexpected channel entries %v", got)
+	}
+}
+
+func TestCollapseTimeoutFlush(t *testing.T) {
+	sink := &memorySink{}
+	AddSink("collapse-timeout", sink)
+	defer RemoveSink("collapse-timeout")
+
+	SetCollapse(&CollapseConfig{Timeout: 20 * time.Millisecond})
+	defer SetCollapse(nil)
+
+	Info("tick")
+	Info("tick")
+	Info("tick")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		sink.mu.Lock()
+		n := len(sink.entries)
+		var last string
+		if n > 0 {
+			last = sink.entries[n-1].Message
+		}
+		sink.mu.Unlock()
+
+		if n == 2 {
+			if last != "last message repeated 2 times" {
+				t.Errorf("Unexpected summary %q", last)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected summary after timeout, got %d entries", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 汇总后相同消息重新计数，不再重复写出原消息
+	Info("tick")
+	sink.mu.Lock()
+	n := len(sink.entries)
+	sink.mu.Unlock()
+	if n != 2 {
+		t.Errorf("Repeat after timeout should be counted, got %d entries", n)
+	}
+}
+
+func TestCollapseIgnoresFilteredEntries(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	SetLevel(LevelInfo)
+	defer SetLevel(LevelDebug)
+
+	SetCollapse(&CollapseConfig{Timeout: time.Hour})
+	Info("same")
+	Debug("hidden")
+	Info("same")
+	SetCollapse(nil)
+
+	if !strings.Contains(buf.String(), "last message repeated 1 times") {
+		t.Errorf("Filtered entries should not break a repeat run:\n%s", buf.String())
+	}
+}
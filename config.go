@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// runtimeConfig 是不可变的运行时配置快照。
+// 日志路径通过 currentConfig 无锁读取；修改在 configMutex 下复制当前快照、修改副本后整体替换，
+// 已发布的快照及其中的 map 不再修改
+type runtimeConfig struct {
+	productName string
+	linePrefix  string // "[name] "，未设置产品名时为空
+	bufferSize  int    // 新建 channel 的默认缓冲区大小
+
+	levels       map[string]LevelSpec // 已注册的级别
+	minLevel     LevelSpec            // 全局最低输出级别
+	loggerLevels map[string]LevelSpec // 命名 logger 的最低级别，覆盖全局设置
+
+	color   bool
+	stdout  io.Writer      // 进程的标准输出，RedirectStdio 期间为原 fd 1 的副本
+	console io.Writer      // 控制台，nil 表示不输出到控制台
+	stderr  io.Writer      // 进程的标准错误和内部警告的输出，RedirectStdio 期间为原 fd 2 的副本
+	custom  io.Writer      // SetOutput 设置的输出
+	reader  *io.PipeWriter // GetReaderCopy 的管道
+	output  io.Writer      // 纯文本行的写入目标，由 rebuildOutput 生成
+}
+
+var (
+	activeConfig atomic.Pointer[runtimeConfig]
+	configMutex  sync.Mutex
+
+	// 在变量初始化阶段完成，保证其他文件的 init 可以使用级别表
+	_ = storeConfig(defaultConfig())
+)
+
+func defaultConfig() *runtimeConfig {
+	levels := make(map[string]LevelSpec, len(builtinLevels))
+	for _, spec := range builtinLevels {
+		levels[spec.Name] = spec
+	}
+
+	c := &runtimeConfig{
+		bufferSize:   100, // 默认缓冲区大小
+		levels:       levels,
+		minLevel:     levels[LevelDebug],
+		loggerLevels: make(map[string]LevelSpec),
+		stdout:       os.Stdout,
+		console:      os.Stdout,
+		stderr:       os.Stderr,
+	}
+	c.rebuildOutput()
+	return c
+}
+
+func storeConfig(c *runtimeConfig) bool {
+	activeConfig.Store(c)
+	return true
+}
+
+// currentConfig 返回当前生效的配置快照，调用方不得修改
+func currentConfig() *runtimeConfig {
+	return activeConfig.Load()
+}
+
+// updateConfig 复制当前快照交给 fn 修改后发布；fn 修改 map 前需先复制。
+// fn 返回错误时不发布任何修改
+func updateConfig(fn func(c *runtimeConfig) error) error {
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	next := *activeConfig.Load()
+	if err := fn(&next); err != nil {
+		return err
+	}
+	activeConfig.Store(&next)
+	return nil
+}
+
+// rebuildOutput 根据控制台、自定义输出和 reader 副本重新生成 output
+func (c *runtimeConfig) rebuildOutput() {
+	var writers []io.Writer
+
+	// 启用颜色时控制台由 writeLine 单独输出
+	if !c.color && c.console != nil {
+		writers = append(writers, c.console)
+	}
+
+	if c.custom != nil {
+		writers = append(writers, c.custom)
+	}
+
+	if c.reader != nil {
+		writers = append(writers, c.reader)
+	}
+
+	switch len(writers) {
+	case 0:
+		c.output = io.Discard
+	case 1:
+		c.output = writers[0]
+	default:
+		c.output = io.MultiWriter(writers...)
+	}
+}
+
+// outputEnabled 判断该级别是否达到输出的最低级别，name 为命名 logger 名称
+func (c *runtimeConfig) outputEnabled(spec LevelSpec, name string) bool {
+	if spec.Always {
+		return true
+	}
+	if name != "" && len(c.loggerLevels) > 0 {
+		if override, ok := c.loggerLevels[name]; ok {
+			return spec.Severity >= override.Severity
+		}
+	}
+	return spec.Severity >= c.minLevel.Severity
+}
+
+// SetProductName updates the prefix
+func SetProductName(name string) {
+	updateConfig(func(c *runtimeConfig) error {
+		c.productName = name
+		c.linePrefix = fmt.Sprintf("[%v] ", name)
+		return nil
+	})
+}
+
+// GetPrefix 获取当前的日志前缀
+func GetPrefix() string {
+	return currentConfig().productName
+}
+
+// SetChannelBufferSize 设置 channel 缓冲区大小
+func SetChannelBufferSize(size int) {
+	if size <= 0 {
+		size = 100 // 默认值
+	}
+	updateConfig(func(c *runtimeConfig) error {
+		c.bufferSize = size
+		return nil
+	})
+}
+
+// SetColor enables or disables ANSI level colors on console output
+// Custom writers always receive plain text
+func SetColor(enabled bool) {
+	updateConfig(func(c *runtimeConfig) error {
+		c.color = enabled
+		c.rebuildOutput()
+		return nil
+	})
+}
+
+// SetOutput sets the output destination for the logger
+// This replaces the default console output
+func SetOutput(w io.Writer) {
+	updateConfig(func(c *runtimeConfig) error {
+		c.custom = w
+		c.rebuildOutput()
+		return nil
+	})
+}
+
+// GetReaderCopy returns a copy of the logger output that can be read from
+// This allows reading log output while still writing to console
+func GetReaderCopy() (io.Reader, error) {
+	var reader *io.PipeReader
+	err := updateConfig(func(c *runtimeConfig) error {
+		if c.custom == nil {
+			return fmt.Errorf("no custom writer set, call SetOutput first")
+		}
+
+		// 如果已经有活跃的 reader，先关闭它
+		if c.reader != nil {
+			c.reader.Close()
+		}
+
+		// 创建新的管道，并更新输出包含管道写入器
+		reader, c.reader = io.Pipe()
+		c.rebuildOutput()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// RemoveReaderCopy removes the reader copy from logger output
+func RemoveReaderCopy() {
+	updateConfig(func(c *runtimeConfig) error {
+		if c.reader != nil {
+			c.reader.Close()
+			c.reader = nil
+			c.rebuildOutput()
+		}
+		return nil
+	})
+}
+
+// warnf 将内部警告写到当前配置的标准错误
+func warnf(format string, args ...any) {
+	fmt.Fprintf(currentConfig().stderr, "WARNING: "+format+"\n", args...)
+}
+
+// stdStream 写入当前配置的标准输出（1）或标准错误（2），
+// 使 RedirectStdio 期间的控制台 sink 写到原来的文件描述符而不是被重定向的管道
+type stdStream int
+
+func (s stdStream) Write(p []byte) (int, error) {
+	c := currentConfig()
+	if s == 2 {
+		return c.stderr.Write(p)
+	}
+	return c.stdout.Write(p)
+}
+
+// copyLevels 复制级别表，用于修改快照中的 map
+func copyLevels(m map[string]LevelSpec, extra int) map[string]LevelSpec {
+	copied := make(map[string]LevelSpec, len(m)+extra)
+	for name, spec := range m {
+		copied[name] = spec
+	}
+	return copied
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// 运行时配置与日志调用并发修改，配合 go test -race 检查数据竞争
+func TestConfigConcurrentUpdates(t *testing.T) {
+	withDiscardOutput(t)
+	defer SetProductName("")
+	defer SetChannelBufferSize(100)
+	defer SetLevel(LevelDebug)
+	defer SetLoggerLevel("config-test", "")
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			named := Named("config-test")
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				Info("message %d", i)
+				named.Warnw("named", Int("i", i))
+				_ = GetPrefix()
+				_ = GetLevel()
+			}
+		}(i)
+	}
+
+	for i := 0; i < 200; i++ {
+		SetProductName(fmt.Sprintf("app-%d", i))
+		SetChannelBufferSize(10 + i)
+		SetLevel([]string{LevelDebug, LevelInfo, LevelWarn}[i%3])
+		SetLoggerLevel("config-test", []string{"", LevelError}[i%2])
+		SetColor(i%2 == 0)
+		name := fmt.Sprintf("config-test-%d", i%4)
+		GetLogChannel(name)
+		RemoveLogChannel(name)
+	}
+	close(stop)
+	wg.Wait()
+	SetColor(false)
+}
+
+// 同一行中的前缀来自同一个配置快照
+func TestConfigSnapshotPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+	defer SetProductName("")
+
+	SetProductName("snap")
+	Info("hello")
+
+	if got := GetPrefix(); got != "snap" {
+		t.Fatalf("GetPrefix() = %q, want %q", got, "snap")
+	}
+	if !strings.Contains(buf.String(), "[snap] [INFO] hello") {
+		t.Fatalf("output %q does not contain prefixed line", buf.String())
+	}
+}
+
+// 失败的修改不发布新快照
+func TestConfigUpdateErrorKeepsSnapshot(t *testing.T) {
+	before := currentConfig()
+	if err := SetLevel("NOPE"); err == nil {
+		t.Fatal("SetLevel with unknown level should fail")
+	}
+	if currentConfig() != before {
+		t.Fatal("failed update replaced the config snapshot")
+	}
+}
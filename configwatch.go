@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval 是 WatchConfig 默认的轮询间隔
+const DefaultWatchInterval = 2 * time.Second
+
+// WatchConfig loads and applies the configuration file at path, then polls
+// its modification time and size every interval (DefaultWatchInterval when
+// <= 0) and applies it again when either changes.
+//
+// Every reload logs an INFO entry summarising what changed. A file that
+// fails to parse or apply is refused with an ERROR entry and the running
+// configuration stays in place. Outputs whose configuration did not change
+// keep running, so entries in flight are not lost.
+//
+// An error loading or applying the initial file is returned and nothing is
+// watched. Call stop to end watching.
+func WatchConfig(path string, interval time.Duration) (stop func(), err error) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	stamp, err := statConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := config.apply(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	w := &configWatcher{path: path, stamp: stamp, done: make(chan struct{})}
+	w.wg.Add(1)
+	go w.run(interval)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(w.done)
+			w.wg.Wait()
+		})
+	}, nil
+}
+
+// configStamp 标识配置文件的一个版本
+type configStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func statConfig(path string) (configStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return configStamp{}, err
+	}
+	return configStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// configWatcher 轮询配置文件并在变化时重新应用
+type configWatcher struct {
+	path    string
+	stamp   configStamp
+	missing bool // 文件暂时不可访问，避免每次轮询都记录错误
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func (w *configWatcher) run(interval time.Duration) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// poll 检查文件是否变化，变化时重新加载
+func (w *configWatcher) poll() {
+	stamp, err := statConfig(w.path)
+	if err != nil {
+		// 编辑器保存时可能短暂删除文件，只在首次失败时记录
+		if !w.missing {
+			w.missing = true
+			Error("config reload failed, keeping current configuration: %v", err)
+		}
+		return
+	}
+	w.missing = false
+	if stamp == w.stamp {
+		return
+	}
+	w.stamp = stamp
+
+	config, err := LoadConfig(w.path)
+	if err != nil {
+		Error("config reload failed, keeping current configuration: %v", err)
+		return
+	}
+	changes, err := config.apply()
+	if err != nil {
+		Error("config reload failed, keeping current configuration: %s: %v", w.path, err)
+		return
+	}
+
+	if len(changes) == 0 {
+		logNotice("config %s reloaded, no changes", w.path)
+		return
+	}
+	logNotice("config %s reloaded: %s", w.path, strings.Join(changes, "; "))
+}
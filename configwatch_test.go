@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rewriteConfig 写入新内容并推后修改时间，保证轮询能观察到变化
+func rewriteConfig(t *testing.T, path, content string, version int) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(time.Duration(version) * time.Second)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitForEntry 等待 sink 收到包含 text 的条目
+func waitForEntry(t *testing.T, sink *memorySink, level, text string) LogEntry {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		sink.mu.Lock()
+		for _, entry := range sink.entries {
+			if entry.Level == level && strings.Contains(entry.Message, text) {
+				sink.mu.Unlock()
+				return entry
+			}
+		}
+		sink.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("no %s entry containing %q", level, text)
+	return LogEntry{}
+}
+
+func TestWatchConfigReloads(t *testing.T) {
+	resetFileConfig(t)
+	sink := &memorySink{}
+	AddSink("watch-test", sink)
+	defer RemoveSink("watch-test")
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	rewriteConfig(t, path, `
+level: info
+outputs: []
+channels:
+  - name: watch-channel
+    level: warn
+`, 0)
+
+	stop, err := WatchConfig(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchConfig: %v", err)
+	}
+	defer stop()
+
+	ch := GetLogChannel("watch-channel")
+	if GetLevel() != LevelInfo {
+		t.Fatalf("initial level = %s, want INFO", GetLevel())
+	}
+
+	rewriteConfig(t, path, `
+level: warn
+outputs: []
+channels:
+  - name: watch-channel
+    level: error
+`, 1)
+	notice := waitForEntry(t, sink, LevelInfo, "reloaded")
+	for _, want := range []string{"level INFO -> WARN", "channel watch-channel level WARN -> ERROR"} {
+		if !strings.Contains(notice.Message, want) {
+			t.Errorf("reload summary %q does not mention %q", notice.Message, want)
+		}
+	}
+	if GetLevel() != LevelWarn {
+		t.Fatalf("level after reload = %s, want WARN", GetLevel())
+	}
+
+	// 订阅方持有的 channel 在重新加载后仍然有效，并使用新的级别
+	Warn("below channel level")
+	Error("channel entry")
+	select {
+	case entry, ok := <-ch:
+		if !ok || entry.Message != "channel entry" {
+			t.Fatalf("channel received %q (open=%v), want the ERROR entry", entry.Message, ok)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel did not receive entry after reload")
+	}
+
+	rewriteConfig(t, path, "level: [", 2)
+	waitForEntry(t, sink, LevelError, "config reload failed")
+	if GetLevel() != LevelWarn {
+		t.Fatalf("invalid config changed level to %s", GetLevel())
+	}
+
+	stop()
+	stop()
+}
+
+func TestWatchConfigInvalidInitialFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logging.json")
+	if err := os.WriteFile(path, []byte(`{"level": "nope"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WatchConfig(path, time.Millisecond); err == nil {
+		t.Fatal("WatchConfig with invalid file should fail")
+	}
+	if _, err := WatchConfig(filepath.Join(t.TempDir(), "missing.json"), time.Millisecond); err == nil {
+		t.Fatal("WatchConfig with missing file should fail")
+	}
+}
+
+func TestApplyKeepsUnchangedOutputs(t *testing.T) {
+	resetFileConfig(t)
+
+	dir := t.TempDir()
+	config := &FileConfig{
+		Level: LevelInfo,
+		Outputs: []OutputConfig{
+			{Name: "keep", Type: "file", Path: filepath.Join(dir, "keep.log")},
+			{Name: "change", Type: "file", Path: filepath.Join(dir, "a.log")},
+		},
+	}
+	if err := config.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	sinkByName := func(name string) Sink {
+		sinksMutex.RLock()
+		defer sinksMutex.RUnlock()
+		return sinks[name]
+	}
+	keep, change := sinkByName("config.keep"), sinkByName("config.change")
+
+	next := *config
+	next.Level = LevelWarn
+	next.Outputs = []OutputConfig{config.Outputs[0], {Name: "change", Type: "file", Path: filepath.Join(dir, "b.log")}}
+	changes, err := next.apply()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sinkByName("config.keep") != keep {
+		t.Error("unchanged output was reopened")
+	}
+	if sinkByName("config.change") == change {
+		t.Error("changed output was not reopened")
+	}
+	want := "level INFO -> WARN; output change changed"
+	if got := strings.Join(changes, "; "); got != want {
+		t.Errorf("changes = %q, want %q", got, want)
+	}
+}
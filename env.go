@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// EnvLogLevel 是配置日志级别的环境变量名
+const EnvLogLevel = "LOG_LEVEL"
+
+// errUnknownLevel 表示级别未注册
+var errUnknownLevel = errors.New("unknown level")
+
+// pendingEnvLevel 保存启动时因引用未注册级别而未能应用的 LOG_LEVEL，
+// 由 RegisterLevel 重试
+var pendingEnvLevel struct {
+	mu    sync.Mutex
+	value string
+}
+
+func init() {
+	err := ConfigureLevelsFromEnv()
+	if errors.Is(err, errUnknownLevel) {
+		pendingEnvLevel.value = os.Getenv(EnvLogLevel)
+		warnf("%v (ignored unless registered later)", err)
+	} else if err != nil {
+		warnf("%v", err)
+	}
+}
+
+// ConfigureLevelsFromEnv applies the level configuration found in the
+// LOG_LEVEL environment variable, e.g. "debug,db=trace,http=warn".
+// It is called automatically at startup. A value naming a custom level that
+// is not registered yet is applied again by RegisterLevel once every level
+// it names exists.
+func ConfigureLevelsFromEnv() error {
+	value, ok := os.LookupEnv(EnvLogLevel)
+	if !ok || strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	if err := ApplyLevelString(value); err != nil {
+		return fmt.Errorf("invalid %s: %w", EnvLogLevel, err)
+	}
+	return nil
+}
+
+// retryEnvLevels 在注册新级别后重试启动时未能应用的 LOG_LEVEL
+func retryEnvLevels() {
+	pendingEnvLevel.mu.Lock()
+	defer pendingEnvLevel.mu.Unlock()
+
+	if pendingEnvLevel.value == "" {
+		return
+	}
+	err := ApplyLevelString(pendingEnvLevel.value)
+	if errors.Is(err, errUnknownLevel) {
+		return
+	}
+	if err != nil {
+		warnf("invalid %s: %v", EnvLogLevel, err)
+	}
+	pendingEnvLevel.value = ""
+}
+
+// ApplyLevelString 解析并应用级别配置字符串
+// 不带名称的项设置全局级别，name=level 形式的项设置命名 logger 级别
+func ApplyLevelString(s string) error {
+	global, named, err := ParseLevelString(s)
+	if err != nil {
+		return err
+	}
+
+	if global != "" {
+		if err := SetLevel(global); err != nil {
+			return err
+		}
+	}
+	for name, level := range named {
+		if err := SetLoggerLevel(name, level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseLevelString 解析级别配置字符串，校验所有级别均已注册
+func ParseLevelString(s string) (global string, named map[string]string, err error) {
+	named = make(map[string]string)
+
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, level, hasName := strings.Cut(item, "=")
+		if !hasName {
+			level = name
+			name = ""
+		}
+		name = strings.TrimSpace(name)
+		level = strings.TrimSpace(level)
+
+		if hasName && name == "" {
+			return "", nil, fmt.Errorf("empty logger name in %q", item)
+		}
+
+		spec, ok := GetLevelSpec(level)
+		if !ok {
+			return "", nil, fmt.Errorf("%w %q", errUnknownLevel, level)
+		}
+
+		if hasName {
+			named[name] = spec.Name
+		} else {
+			global = spec.Name
+		}
+	}
+
+	return global, named, nil
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseLevelString(t *testing.T) {
+	global, named, err := ParseLevelString("debug, db=trace ,http=WARN")
+	if err != nil {
+		t.Fatalf("ParseLevelString failed: %v", err)
+	}
+
+	if global != LevelDebug {
+		t.Errorf("Expected global DEBUG, got %q", global)
+	}
+	if named["db"] != LevelTrace || named["http"] != LevelWarn {
+		t.Errorf("Unexpected named levels: %v", named)
+	}
+
+	if _, _, err := ParseLevelString("verbose"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+	if _, _, err := ParseLevelString("=debug"); err == nil {
+		t.Error("Expected error for empty logger name")
+	}
+}
+
+func TestConfigureLevelsFromEnv(t *testing.T) {
+	t.Setenv(EnvLogLevel, "warn,envdb=trace")
+	defer SetLevel(LevelDebug)
+	defer SetLoggerLevel("envdb", "")
+
+	if err := ConfigureLevelsFromEnv(); err != nil {
+		t.Fatalf("ConfigureLevelsFromEnv failed: %v", err)
+	}
+
+	if GetLevel() != LevelWarn {
+		t.Errorf("Expected global WARN, got %s", GetLevel())
+	}
+	if Named("envdb").GetLevel() != LevelTrace {
+		t.Errorf("Expected envdb TRACE, got %s", Named("envdb").GetLevel())
+	}
+	if Named("other").GetLevel() != LevelWarn {
+		t.Errorf("Expected other to inherit WARN, got %s", Named("other").GetLevel())
+	}
+
+	t.Setenv(EnvLogLevel, "loud")
+	if err := ConfigureLevelsFromEnv(); err == nil {
+		t.Error("Expected error for invalid LOG_LEVEL")
+	}
+}
+
+func TestEnvLevelAppliedOnRegister(t *testing.T) {
+	t.Setenv(EnvLogLevel, "info,envaudit=envcustom")
+	defer SetLevel(LevelDebug)
+	defer SetLoggerLevel("envaudit", "")
+
+	// 模拟启动时自定义级别尚未注册
+	err := ConfigureLevelsFromEnv()
+	if !errors.Is(err, errUnknownLevel) {
+		t.Fatalf("Expected unknown level error, got %v", err)
+	}
+	pendingEnvLevel.value = os.Getenv(EnvLogLevel)
+	defer func() { pendingEnvLevel.value = "" }()
+
+	if err := RegisterLevel(LevelSpec{Name: "envcustom", Severity: 6, Always: true}); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+	if GetLevel() != LevelInfo {
+		t.Errorf("Expected global INFO, got %s", GetLevel())
+	}
+	if Named("envaudit").GetLevel() != "ENVCUSTOM" {
+		t.Errorf("Expected envaudit ENVCUSTOM, got %s", Named("envaudit").GetLevel())
+	}
+	if pendingEnvLevel.value != "" {
+		t.Error("Applied LOG_LEVEL should no longer be pending")
+	}
+}
+
+func TestNamedLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	db := Named("db")
+	if Named("db") != db {
+		t.Error("Named should return the same logger for the same name")
+	}
+
+	SetLevel(LevelInfo)
+	defer SetLevel(LevelDebug)
+	db.SetLevel(LevelTrace)
+	defer db.SetLevel("")
+
+	db.Trace("query plan")
+	Debug("global debug")
+
+	content := buf.String()
+	if !strings.Contains(content, "[TRACE] [db] query plan") {
+		t.Errorf("Named logger TRACE not found: %s", content)
+	}
+	if strings.Contains(content, "global debug") {
+		t.Error("Global DEBUG should be filtered at INFO level")
+	}
+}
@@ -0,0 +1,14 @@
+//go:build linux
+
+package logger
+
+import "syscall"
+
+func dupFD(fd int) (int, error) {
+	return syscall.Dup(fd)
+}
+
+// redirectFD 使 newfd 指向 oldfd 引用的文件；部分 linux 架构没有 dup2，统一使用 dup3
+func redirectFD(oldfd, newfd int) error {
+	return syscall.Dup3(oldfd, newfd, 0)
+}
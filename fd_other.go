@@ -0,0 +1,16 @@
+//go:build !unix
+
+package logger
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func dupFD(fd int) (int, error) {
+	return -1, fmt.Errorf("redirecting stdio is not supported on %s", runtime.GOOS)
+}
+
+func redirectFD(oldfd, newfd int) error {
+	return fmt.Errorf("redirecting stdio is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,14 @@
+//go:build unix && !linux
+
+package logger
+
+import "syscall"
+
+func dupFD(fd int) (int, error) {
+	return syscall.Dup(fd)
+}
+
+// redirectFD 使 newfd 指向 oldfd 引用的文件
+func redirectFD(oldfd, newfd int) error {
+	return syscall.Dup2(oldfd, newfd)
+}
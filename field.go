@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Field is a structured key/value pair attached to a log entry.
+//
+// Fields created with F hold their value in Value. The typed constructors
+// (String, Int, Int64, Uint64, Float64, Bool, Duration) store the value
+// without boxing it in an interface, so they do not allocate; their Value
+// is nil. Use Any to read the value of any field.
+type Field struct {
+	Key   string
+	Value any
+
+	kind fieldKind
+	num  uint64
+	str  string
+}
+
+// fieldKind 表示字段值的存储方式
+type fieldKind uint8
+
+const (
+	kindAny fieldKind = iota // 值保存在 Value 中
+	kindString
+	kindInt
+	kindInt64
+	kindUint64
+	kindFloat64
+	kindBool
+	kindDuration
+)
+
+// F 创建一个结构化字段
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String 创建字符串字段
+func String(key, value string) Field {
+	return Field{Key: key, kind: kindString, str: value}
+}
+
+// Int 创建 int 字段
+func Int(key string, value int) Field {
+	return Field{Key: key, kind: kindInt, num: uint64(value)}
+}
+
+// Int64 创建 int64 字段
+func Int64(key string, value int64) Field {
+	return Field{Key: key, kind: kindInt64, num: uint64(value)}
+}
+
+// Uint64 创建 uint64 字段
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, kind: kindUint64, num: value}
+}
+
+// Float64 创建 float64 字段
+func Float64(key string, value float64) Field {
+	return Field{Key: key, kind: kindFloat64, num: math.Float64bits(value)}
+}
+
+// Bool 创建 bool 字段
+func Bool(key string, value bool) Field {
+	var n uint64
+	if value {
+		n = 1
+	}
+	return Field{Key: key, kind: kindBool, num: n}
+}
+
+// Duration 创建 time.Duration 字段
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, kind: kindDuration, num: uint64(value)}
+}
+
+// Any 返回字段值，类型化字段在此时装箱
+func (f Field) Any() any {
+	switch f.kind {
+	case kindString:
+		return f.str
+	case kindInt:
+		return int(f.num)
+	case kindInt64:
+		return int64(f.num)
+	case kindUint64:
+		return f.num
+	case kindFloat64:
+		return math.Float64frombits(f.num)
+	case kindBool:
+		return f.num != 0
+	case kindDuration:
+		return time.Duration(f.num)
+	default:
+		return f.Value
+	}
+}
+
+// String 返回字段值的文本形式
+func (f Field) String() string {
+	if f.kind == kindAny {
+		return fmt.Sprint(f.Value)
+	}
+	return string(f.appendValue(nil))
+}
+
+// appendValue 追加字段值的文本形式，类型化字段不分配内存
+func (f Field) appendValue(buf []byte) []byte {
+	switch f.kind {
+	case kindString:
+		return append(buf, f.str...)
+	case kindInt, kindInt64:
+		return strconv.AppendInt(buf, int64(f.num), 10)
+	case kindUint64:
+		return strconv.AppendUint(buf, f.num, 10)
+	case kindFloat64:
+		return strconv.AppendFloat(buf, math.Float64frombits(f.num), 'g', -1, 64)
+	case kindBool:
+		return strconv.AppendBool(buf, f.num != 0)
+	case kindDuration:
+		return append(buf, time.Duration(f.num).String()...)
+	}
+
+	v := resolveLogValue(f.Value)
+	if s, ok := v.(string); ok {
+		return append(buf, s...)
+	}
+	return fmt.Append(buf, v)
+}
+
+// formatFields 将字段格式化为 " key=value ..." 形式，用于文本输出
+func formatFields(fields []Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	return string(appendFields(nil, fields))
+}
+
+// appendFields 以 " key=value" 形式追加字段，值包含空白、引号或等号时加引号
+func appendFields(buf []byte, fields []Field) []byte {
+	for _, f := range fields {
+		buf = append(buf, ' ')
+		buf = append(buf, f.Key...)
+		buf = append(buf, '=')
+
+		start := len(buf)
+		buf = f.appendValue(buf)
+		if needsQuote(buf[start:]) {
+			// 在缓冲区尾部生成带引号的形式后移回原位
+			end := len(buf)
+			buf = strconv.AppendQuote(buf, string(buf[start:end]))
+			buf = append(buf[:start], buf[end:]...)
+		}
+	}
+	return buf
+}
+
+// needsQuote 判断值是否为空或包含空白、引号、等号
+func needsQuote(b []byte) bool {
+	if len(b) == 0 {
+		return true
+	}
+	for _, c := range b {
+		switch c {
+		case ' ', '\t', '\r', '\n', '"', '=':
+			return true
+		}
+	}
+	return false
+}
+
+// mergeFields 将调用时传入的字段追加到 logger 字段之后，返回新切片并解析其中的 LogValuer
+func mergeFields(base, extra []Field) []Field {
+	merged := make([]Field, 0, len(base)+len(extra))
+	merged = append(merged, base...)
+	merged = append(merged, extra...)
+	for i := len(base); i < len(merged); i++ {
+		if merged[i].kind == kindAny {
+			merged[i].Value = resolveLogValue(merged[i].Value)
+		}
+	}
+	return merged
+}
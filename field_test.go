@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypedFields(t *testing.T) {
+	fields := []Field{
+		String("path", "/api v1"),
+		Int("status", 200),
+		Int64("bytes", -5),
+		Uint64("id", 18446744073709551615),
+		Float64("ratio", 0.25),
+		Bool("cached", true),
+		Duration("took", 1500*time.Millisecond),
+		F("user", "alice"),
+	}
+
+	want := ` path="/api v1" status=200 bytes=-5 id=18446744073709551615 ratio=0.25 cached=true took=1.5s user=alice`
+	if got := formatFields(fields); got != want {
+		t.Errorf("Unexpected text:\n got %q\nwant %q", got, want)
+	}
+
+	values := []any{"/api v1", 200, int64(-5), uint64(18446744073709551615), 0.25, true, 1500 * time.Millisecond, "alice"}
+	for i, f := range fields {
+		if f.Any() != values[i] {
+			t.Errorf("%s: Any() = %#v, want %#v", f.Key, f.Any(), values[i])
+		}
+	}
+
+	data, err := json.Marshal(LogEntry{Level: LevelInfo, Fields: fields[:3]})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"fields":{"path":"/api v1","status":200,"bytes":-5}`) {
+		t.Errorf("Typed fields not encoded: %s", data)
+	}
+}
+
+func TestInfowDirectAndEntryPaths(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	l := Named("fieldtest").With(String("req", "r1"))
+
+	// 无 channel 和 sink 时直接写出
+	l.Infow("100% done\n", Int("n", 3))
+	direct := buf.String()
+	buf.Reset()
+
+	// 有 channel 时构造条目
+	ch := GetLogChannel("field-test")
+	defer RemoveLogChannel("field-test")
+	l.Infow("100% done\n", Int("n", 3))
+	viaEntry := buf.String()
+
+	for _, out := range []string{direct, viaEntry} {
+		if !strings.HasSuffix(out, "[INFO] [fieldtest] 100% done req=r1 n=3\n") {
+			t.Errorf("Unexpected line %q", out)
+		}
+	}
+	if direct[len(TextTimeLayout):] != viaEntry[len(TextTimeLayout):] {
+		t.Errorf("Direct and entry paths differ:\n%q\n%q", direct, viaEntry)
+	}
+
+	entry := <-ch
+	if len(entry.Fields) != 2 || entry.Fields[1].Any() != 3 || entry.File == "" || !strings.HasSuffix(entry.File, "field_test.go") {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+}
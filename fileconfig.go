@@ -0,0 +1,892 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileConfig is a declarative logging configuration, usually read with
+// LoadConfig. The file is authoritative: Apply replaces levels, outputs,
+// sampling, redaction and collapsing with what the file describes, and
+// sections that are absent are disabled.
+//
+// Durations are strings accepted by time.ParseDuration, e.g. "1s".
+type FileConfig struct {
+	// Product 设置输出前缀，为空时保持当前名称
+	Product string `json:"product,omitempty"`
+	// Level 使用 LOG_LEVEL 的语法，如 "info,db=debug"，为空时为 DEBUG
+	Level string `json:"level,omitempty"`
+	// Loggers 设置命名 logger 的级别，覆盖 Level 中的同名项
+	Loggers map[string]string `json:"loggers,omitempty"`
+	// Color 为控制台文本输出启用颜色
+	Color bool `json:"color,omitempty"`
+	// ChannelBufferSize 是 GetLogChannel 的默认缓冲区大小，默认 100
+	ChannelBufferSize int `json:"channel_buffer_size,omitempty"`
+
+	// Outputs 为 nil 时只输出到控制台，空列表表示不输出
+	Outputs   []OutputConfig       `json:"outputs"`
+	Channels  []ChannelFileConfig  `json:"channels,omitempty"`
+	Sampling  *SamplingFileConfig  `json:"sampling,omitempty"`
+	Redaction *RedactionFileConfig `json:"redaction,omitempty"`
+	Collapse  *CollapseFileConfig  `json:"collapse,omitempty"`
+}
+
+// OutputConfig describes one output. Type selects which of the other
+// fields apply:
+//
+//	console   stream, format
+//	file      path, format
+//	rotating  path, max_size, max_backups, format
+//	syslog    network, address, syslog_format, facility, app_name
+//	network   network, address, framing, format
+//	http      url, encoder, index, headers, gzip
+//	otlp      endpoint, format ("protobuf" (default) or "json"), headers, gzip
+//
+// A console output with text format on stdout and no level is the
+// builtin console output and honours Color; every other output is
+// registered as a sink named "config.<name>".
+type OutputConfig struct {
+	Name   string `json:"name,omitempty"`   // 默认与 Type 相同，名称不能重复
+	Type   string `json:"type"`             // 输出类型
+	Format string `json:"format,omitempty"` // "text" 或 "json"
+	Level  string `json:"level,omitempty"`  // 该输出的最低级别，只能进一步限制全局级别
+
+	Stream string `json:"stream,omitempty"` // console: "stdout"（默认）或 "stderr"
+
+	Path       string `json:"path,omitempty"`
+	MaxSize    int64  `json:"max_size,omitempty"`    // rotating: 字节数，默认 DefaultRotateSize
+	MaxBackups int    `json:"max_backups,omitempty"` // rotating: 保留的轮转文件数
+
+	Network      string `json:"network,omitempty"`
+	Address      string `json:"address,omitempty"`
+	Framing      string `json:"framing,omitempty"`       // network: "newline"（默认）或 "length"
+	SyslogFormat string `json:"syslog_format,omitempty"` // syslog: "rfc5424"（默认）或 "rfc3164"
+	Facility     int    `json:"facility,omitempty"`
+	AppName      string `json:"app_name,omitempty"`
+
+	URL      string            `json:"url,omitempty"`
+	Endpoint string            `json:"endpoint,omitempty"`
+	Encoder  string            `json:"encoder,omitempty"` // http: "ndjson"（默认）、"loki" 或 "elasticsearch"
+	Index    string            `json:"index,omitempty"`   // http: elasticsearch 索引
+	Headers  map[string]string `json:"headers,omitempty"`
+	Gzip     bool              `json:"gzip,omitempty"`
+}
+
+// ChannelFileConfig 描述预先注册的命名 channel
+type ChannelFileConfig struct {
+	Name       string `json:"name"`
+	BufferSize int    `json:"buffer_size,omitempty"` // 默认 ChannelBufferSize
+	Level      string `json:"level,omitempty"`
+	Timeout    string `json:"timeout,omitempty"`
+	// Policy 是缓冲区满时的处理方式，目前只支持 "drop_oldest"
+	Policy string `json:"policy,omitempty"`
+}
+
+// SamplingFileConfig 对应 SamplingConfig
+type SamplingFileConfig struct {
+	Interval   string               `json:"interval,omitempty"`
+	First      int                  `json:"first,omitempty"`
+	Thereafter int                  `json:"thereafter,omitempty"`
+	RateLimits map[string]RateLimit `json:"rate_limits,omitempty"`
+}
+
+// RedactionFileConfig 对应 RedactionConfig
+type RedactionFileConfig struct {
+	// Rules 是启用的内置规则名称，为 nil 时启用全部内置规则
+	Rules []string `json:"rules"`
+	// Patterns 是附加的正则规则
+	Patterns   []RedactionPattern `json:"patterns,omitempty"`
+	DenyFields []string           `json:"deny_fields"`
+	Mask       string             `json:"mask,omitempty"`
+}
+
+// RedactionPattern 是配置文件中的正则脱敏规则
+type RedactionPattern struct {
+	Name    string `json:"name,omitempty"`
+	Pattern string `json:"pattern"`
+	Mask    string `json:"mask,omitempty"` // 默认 RedactionFileConfig.Mask
+}
+
+// CollapseFileConfig 对应 CollapseConfig
+type CollapseFileConfig struct {
+	Timeout  string `json:"timeout,omitempty"`
+	Channels bool   `json:"channels,omitempty"`
+}
+
+// LoadConfig reads a configuration file. Files ending in .json are JSON,
+// files ending in .yaml or .yml use a simple YAML subset (block mappings,
+// "- " lists, scalars and one-line [a, b] lists); other files are parsed as
+// JSON if they start with '{' and as YAML otherwise. The configuration is
+// validated but not applied.
+func LoadConfig(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config *FileConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		config, err = decodeConfig(data)
+	case ".yaml", ".yml":
+		config, err = decodeYAMLConfig(data)
+	default:
+		config, err = ParseConfig(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseConfig parses and validates a JSON or YAML configuration
+func ParseConfig(data []byte) (*FileConfig, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return decodeConfig(data)
+	}
+	return decodeYAMLConfig(data)
+}
+
+func decodeYAMLConfig(data []byte) (*FileConfig, error) {
+	tree, err := parseYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := tree.(map[string]any); !ok {
+		return nil, fmt.Errorf("configuration must be a mapping")
+	}
+	data, err = json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	return decodeConfig(data)
+}
+
+// decodeConfig 解析 JSON 配置，拒绝未知字段以便发现拼写错误
+func decodeConfig(data []byte) (*FileConfig, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	config := &FileConfig{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, err
+	}
+	if _, err := config.compile(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// configPlan 是校验并解析后的配置
+type configPlan struct {
+	global    string
+	named     map[string]string
+	console   bool // 是否启用内置控制台输出
+	outputs   []OutputConfig
+	channels  map[string]LogChannelConfig
+	sampling  *SamplingConfig
+	redaction *RedactionConfig
+	collapse  *CollapseConfig
+}
+
+// compile 校验配置并解析级别、时长和正则，不打开任何输出
+func (c *FileConfig) compile() (*configPlan, error) {
+	plan := &configPlan{global: LevelDebug, named: make(map[string]string)}
+
+	if strings.TrimSpace(c.Level) != "" {
+		global, named, err := ParseLevelString(c.Level)
+		if err != nil {
+			return nil, fmt.Errorf("level: %w", err)
+		}
+		if global != "" {
+			plan.global = global
+		}
+		plan.named = named
+	}
+	for name, level := range c.Loggers {
+		spec, ok := GetLevelSpec(level)
+		if !ok {
+			return nil, fmt.Errorf("loggers: unknown level %q for %q", level, name)
+		}
+		plan.named[name] = spec.Name
+	}
+	if c.ChannelBufferSize < 0 {
+		return nil, fmt.Errorf("channel_buffer_size must not be negative")
+	}
+
+	if c.Outputs == nil {
+		plan.console = true
+	}
+	seen := make(map[string]bool)
+	for i, o := range c.Outputs {
+		o, err := o.normalize()
+		if err != nil {
+			return nil, fmt.Errorf("outputs[%d]: %w", i, err)
+		}
+		if seen[o.Name] {
+			return nil, fmt.Errorf("outputs[%d]: duplicate output name %q", i, o.Name)
+		}
+		seen[o.Name] = true
+
+		if o.builtinConsole() {
+			plan.console = true
+			continue
+		}
+		plan.outputs = append(plan.outputs, o)
+	}
+
+	var err error
+	if plan.channels, err = c.compileChannels(); err != nil {
+		return nil, err
+	}
+	if plan.sampling, err = c.Sampling.compile(); err != nil {
+		return nil, fmt.Errorf("sampling: %w", err)
+	}
+	if plan.redaction, err = c.Redaction.compile(); err != nil {
+		return nil, fmt.Errorf("redaction: %w", err)
+	}
+	if plan.collapse, err = c.Collapse.compile(); err != nil {
+		return nil, fmt.Errorf("collapse: %w", err)
+	}
+	return plan, nil
+}
+
+// normalize 填充默认值并校验输出配置
+func (o OutputConfig) normalize() (OutputConfig, error) {
+	o.Type = strings.ToLower(o.Type)
+	o.Format = strings.ToLower(o.Format)
+	if o.Name == "" {
+		o.Name = o.Type
+	}
+	if o.Level != "" {
+		spec, ok := GetLevelSpec(o.Level)
+		if !ok {
+			return o, fmt.Errorf("unknown level %q", o.Level)
+		}
+		o.Level = spec.Name
+	}
+
+	formats := []string{"text", "json"}
+	switch o.Type {
+	case "console":
+		if o.Stream == "" {
+			o.Stream = "stdout"
+		}
+		if o.Stream != "stdout" && o.Stream != "stderr" {
+			return o, fmt.Errorf("unknown stream %q", o.Stream)
+		}
+	case "file", "rotating":
+		if o.Path == "" {
+			return o, fmt.Errorf("%s output requires a path", o.Type)
+		}
+	case "syslog":
+		formats = nil
+		if o.SyslogFormat != "" && o.SyslogFormat != "rfc5424" && o.SyslogFormat != "rfc3164" {
+			return o, fmt.Errorf("unknown syslog_format %q", o.SyslogFormat)
+		}
+	case "network":
+		if o.Format == "" {
+			o.Format = "json"
+		}
+		if o.Framing != "" && o.Framing != "newline" && o.Framing != "length" {
+			return o, fmt.Errorf("unknown framing %q", o.Framing)
+		}
+		if o.Address == "" {
+			return o, fmt.Errorf("network output requires an address")
+		}
+		if !supportedNetwork(o.Network) {
+			return o, fmt.Errorf("unsupported network %q (tcp, udp, unix, unixgram or their 4/6 variants)", o.Network)
+		}
+	case "http":
+		formats = nil
+		if o.URL == "" {
+			return o, fmt.Errorf("http output requires a url")
+		}
+		switch o.Encoder {
+		case "", "ndjson", "loki", "elasticsearch":
+		default:
+			return o, fmt.Errorf("unknown encoder %q", o.Encoder)
+		}
+	case "otlp":
+		// 与 NewOTLPSink 一致默认使用 protobuf
+		formats = []string{"protobuf", "json"}
+		if o.Endpoint == "" {
+			return o, fmt.Errorf("otlp output requires an endpoint")
+		}
+	case "":
+		return o, fmt.Errorf("output type is required")
+	default:
+		return o, fmt.Errorf("unknown output type %q", o.Type)
+	}
+
+	if o.Format != "" && !containsString(formats, o.Format) {
+		return o, fmt.Errorf("format %q is not supported by %s outputs", o.Format, o.Type)
+	}
+	if o.Format == "" && len(formats) > 0 {
+		o.Format = formats[0]
+	}
+	return o, nil
+}
+
+// builtinConsole 判断输出是否由内置控制台输出承担
+func (o OutputConfig) builtinConsole() bool {
+	return o.Type == "console" && o.Stream == "stdout" && o.Format == "text" && o.Level == ""
+}
+
+// open 创建输出对应的 sink
+func (o OutputConfig) open() (Sink, error) {
+	formatter := Formatter(TextFormatter{})
+	if o.Format == "json" {
+		formatter = JSONFormatter{}
+	}
+
+	switch o.Type {
+	case "console":
+		w := stdStream(1)
+		if o.Stream == "stderr" {
+			w = stdStream(2)
+		}
+		return &writerSink{w: w, formatter: formatter}, nil
+	case "file":
+		if err := os.MkdirAll(filepath.Dir(o.Path), 0o755); err != nil {
+			return nil, err
+		}
+		file, err := os.OpenFile(o.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		return &writerSink{w: file, closer: file, formatter: formatter}, nil
+	case "rotating":
+		file, err := NewRotatingFile(o.Path, o.MaxSize, o.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		return &writerSink{w: file, closer: file, formatter: formatter}, nil
+	case "syslog":
+		format := RFC5424
+		if o.SyslogFormat == "rfc3164" {
+			format = RFC3164
+		}
+		return NewSyslogSink(SyslogConfig{
+			Network:  o.Network,
+			Address:  o.Address,
+			Format:   format,
+			Facility: o.Facility,
+			AppName:  o.AppName,
+		})
+	case "network":
+		framing := FrameNewline
+		if o.Framing == "length" {
+			framing = FrameLengthPrefix
+		}
+		return NewNetworkSink(NetworkConfig{
+			Network:   o.Network,
+			Address:   o.Address,
+			Framing:   framing,
+			Formatter: formatter,
+		})
+	case "http":
+		var encoder BatchEncoder = NDJSONEncoder{}
+		switch o.Encoder {
+		case "loki":
+			encoder = LokiEncoder{}
+		case "elasticsearch":
+			encoder = ElasticsearchEncoder{Index: o.Index}
+		}
+		return NewHTTPSink(HTTPConfig{
+			URL:     o.URL,
+			Encoder: encoder,
+			Headers: httpHeaders(o.Headers),
+			Gzip:    o.Gzip,
+		})
+	default: // otlp
+		return NewOTLPSink(OTLPConfig{
+			Endpoint: o.Endpoint,
+			JSON:     o.Format == "json",
+			Headers:  httpHeaders(o.Headers),
+			Gzip:     o.Gzip,
+		})
+	}
+}
+
+func httpHeaders(headers map[string]string) http.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+	h := make(http.Header, len(headers))
+	for k, v := range headers {
+		h.Set(k, v)
+	}
+	return h
+}
+
+func (c *FileConfig) compileChannels() (map[string]LogChannelConfig, error) {
+	channels := make(map[string]LogChannelConfig, len(c.Channels))
+	for i, ch := range c.Channels {
+		if ch.Name == "" {
+			return nil, fmt.Errorf("channels[%d]: name is required", i)
+		}
+		if _, dup := channels[ch.Name]; dup {
+			return nil, fmt.Errorf("channels[%d]: duplicate channel %q", i, ch.Name)
+		}
+		if ch.Policy != "" && ch.Policy != "drop_oldest" {
+			return nil, fmt.Errorf("channels[%d]: unsupported policy %q", i, ch.Policy)
+		}
+
+		config := LogChannelConfig{BufferSize: ch.BufferSize, Timeout: 100 * time.Millisecond}
+		if config.BufferSize <= 0 {
+			config.BufferSize = c.ChannelBufferSize
+		}
+		if config.BufferSize <= 0 {
+			config.BufferSize = 100
+		}
+		if ch.Level != "" {
+			spec, ok := GetLevelSpec(ch.Level)
+			if !ok {
+				return nil, fmt.Errorf("channels[%d]: unknown level %q", i, ch.Level)
+			}
+			config.Level = spec.Name
+		}
+		if ch.Timeout != "" {
+			d, err := time.ParseDuration(ch.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("channels[%d]: %w", i, err)
+			}
+			config.Timeout = d
+		}
+		channels[ch.Name] = config
+	}
+	return channels, nil
+}
+
+func (s *SamplingFileConfig) compile() (*SamplingConfig, error) {
+	if s == nil {
+		return nil, nil
+	}
+	config := &SamplingConfig{First: s.First, Thereafter: s.Thereafter}
+	if s.Interval != "" {
+		d, err := time.ParseDuration(s.Interval)
+		if err != nil {
+			return nil, err
+		}
+		config.Interval = d
+	}
+	if len(s.RateLimits) > 0 {
+		config.RateLimits = make(map[string]RateLimit, len(s.RateLimits))
+		for level, limit := range s.RateLimits {
+			spec, ok := GetLevelSpec(level)
+			if !ok {
+				return nil, fmt.Errorf("unknown level %q", level)
+			}
+			if limit.Rate <= 0 {
+				return nil, fmt.Errorf("rate for %s must be positive", spec.Name)
+			}
+			config.RateLimits[spec.Name] = limit
+		}
+	}
+	return config, nil
+}
+
+func (r *RedactionFileConfig) compile() (*RedactionConfig, error) {
+	if r == nil {
+		return nil, nil
+	}
+	config := &RedactionConfig{DenyFields: r.DenyFields, Mask: r.Mask}
+	mask := r.Mask
+	if mask == "" {
+		mask = DefaultRedactionMask
+	}
+
+	builtin := DefaultRedactionRules()
+	if r.Rules == nil {
+		config.Rules = builtin
+	} else {
+		config.Rules = []RedactionRule{}
+		for _, name := range r.Rules {
+			found := false
+			for _, rule := range builtin {
+				if rule.Name == name {
+					config.Rules = append(config.Rules, rule)
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown rule %q", name)
+			}
+		}
+	}
+
+	for i, p := range r.Patterns {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("patterns[%d]: %w", i, err)
+		}
+		rule := RedactionRule{Name: p.Name, Pattern: re, Mask: p.Mask}
+		if rule.Mask == "" {
+			rule.Mask = mask
+		}
+		config.Rules = append(config.Rules, rule)
+	}
+	return config, nil
+}
+
+func (c *CollapseFileConfig) compile() (*CollapseConfig, error) {
+	if c == nil {
+		return nil, nil
+	}
+	config := &CollapseConfig{Channels: c.Channels}
+	if c.Timeout != "" {
+		d, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		config.Timeout = d
+	}
+	return config, nil
+}
+
+// configSinkPrefix 是配置文件创建的 sink 名称前缀
+const configSinkPrefix = "config."
+
+var (
+	// 上一次 Apply 的结果，下一次 Apply 据此复用未变化的输出并移除不再存在的输出和 channel
+	appliedConfig  *FileConfig
+	configOutputs  = make(map[string]OutputConfig) // sink 名称到输出配置
+	configChannels = make(map[string]bool)
+	configMu       sync.Mutex
+)
+
+// Apply applies the configuration to the package level logger and every
+// named logger. New and changed outputs are opened before anything
+// changes, so an error leaves the current configuration in place; outputs
+// whose configuration is unchanged keep running. Outputs added by
+// SetOutput and sinks added with AddSink are not affected.
+func (c *FileConfig) Apply() error {
+	_, err := c.apply()
+	return err
+}
+
+// apply 应用配置并返回变更描述
+func (c *FileConfig) apply() ([]string, error) {
+	plan, err := c.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	// 只打开新增或配置变化的输出，未变化的 sink 保持不动，不丢失其中排队的条目
+	var changed []OutputConfig
+	outputs := make(map[string]OutputConfig, len(plan.outputs))
+	for _, o := range plan.outputs {
+		name := configSinkPrefix + o.Name
+		outputs[name] = o
+		if old, ok := configOutputs[name]; !ok || !reflect.DeepEqual(old, o) {
+			changed = append(changed, o)
+		}
+	}
+	opened, err := openOutputs(changed, configSinkPrefix, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	before := currentConfig()
+	updateConfig(func(rc *runtimeConfig) error {
+		if c.Product != "" {
+			rc.productName = c.Product
+			rc.linePrefix = fmt.Sprintf("[%v] ", c.Product)
+		}
+		rc.minLevel = rc.levels[plan.global]
+		rc.loggerLevels = make(map[string]LevelSpec, len(plan.named))
+		for name, level := range plan.named {
+			rc.loggerLevels[name] = rc.levels[level]
+		}
+		rc.bufferSize = c.ChannelBufferSize
+		if rc.bufferSize <= 0 {
+			rc.bufferSize = 100
+		}
+		rc.color = c.Color
+		rc.console = nil
+		if plan.console {
+			rc.console = rc.stdout
+		}
+		rc.rebuildOutput()
+
+		changes = diffRuntimeConfig(before, rc)
+		return nil
+	})
+
+	// AddSink 在正在进行的写入完成后替换 sink，再关闭旧 sink 使其发送剩余条目
+	for _, o := range changed {
+		name := configSinkPrefix + o.Name
+		if _, existed := configOutputs[name]; existed {
+			changes = append(changes, fmt.Sprintf("output %s changed", o.Name))
+		} else {
+			changes = append(changes, fmt.Sprintf("output %s added", o.Name))
+		}
+		AddSink(name, opened[name])
+	}
+	for _, name := range sortedKeys(configOutputs) {
+		if _, ok := outputs[name]; !ok {
+			RemoveSink(name)
+			changes = append(changes, fmt.Sprintf("output %s removed", strings.TrimPrefix(name, configSinkPrefix)))
+		}
+	}
+	configOutputs = outputs
+
+	changes = append(changes, applyChannels(plan.channels)...)
+
+	var previous FileConfig
+	if appliedConfig != nil {
+		previous = *appliedConfig
+	}
+	if !reflect.DeepEqual(previous.Sampling, c.Sampling) {
+		changes = append(changes, describeSection("sampling", previous.Sampling == nil, c.Sampling == nil))
+	}
+	if !reflect.DeepEqual(previous.Redaction, c.Redaction) {
+		changes = append(changes, describeSection("redaction", previous.Redaction == nil, c.Redaction == nil))
+	}
+	if !reflect.DeepEqual(previous.Collapse, c.Collapse) {
+		changes = append(changes, describeSection("collapse", previous.Collapse == nil, c.Collapse == nil))
+	}
+	SetSampling(plan.sampling)
+	SetRedaction(plan.redaction)
+	SetCollapse(plan.collapse)
+
+	applied := *c
+	appliedConfig = &applied
+	return changes, nil
+}
+
+// applyChannels 注册新的 channel，更新已有 channel 的级别和超时，移除配置中删除的 channel。
+// 被移除的 channel 关闭后，订阅方仍可读完其中缓冲的条目
+func applyChannels(channels map[string]LogChannelConfig) []string {
+	var changes []string
+	for _, name := range sortedKeys(channels) {
+		want := channels[name]
+		current, exists := logChannelConfig(name)
+		if !exists {
+			GetLogChannelWithConfig(name, want)
+			changes = append(changes, fmt.Sprintf("channel %s added", name))
+			continue
+		}
+
+		if current.Level != want.Level || current.Timeout != want.Timeout {
+			reconfigureLogChannel(name, want)
+			changes = append(changes, fmt.Sprintf("channel %s level %s -> %s", name, levelOrAll(current.Level), levelOrAll(want.Level)))
+		}
+		if current.BufferSize != want.BufferSize {
+			changes = append(changes, fmt.Sprintf("channel %s keeps buffer size %d, remove it to apply %d", name, current.BufferSize, want.BufferSize))
+		}
+	}
+
+	for _, name := range sortedKeys(configChannels) {
+		if _, ok := channels[name]; !ok {
+			RemoveLogChannel(name)
+			changes = append(changes, fmt.Sprintf("channel %s removed", name))
+		}
+	}
+	configChannels = make(map[string]bool, len(channels))
+	for name := range channels {
+		configChannels[name] = true
+	}
+	return changes
+}
+
+// diffRuntimeConfig 描述两个运行时配置快照在前缀、级别、缓冲区大小和控制台输出上的差异
+func diffRuntimeConfig(before, after *runtimeConfig) []string {
+	var changes []string
+	if before.productName != after.productName {
+		changes = append(changes, fmt.Sprintf("product %q -> %q", before.productName, after.productName))
+	}
+	if before.minLevel.Name != after.minLevel.Name {
+		changes = append(changes, fmt.Sprintf("level %s -> %s", before.minLevel.Name, after.minLevel.Name))
+	}
+
+	names := make(map[string]LevelSpec, len(before.loggerLevels)+len(after.loggerLevels))
+	for name, spec := range before.loggerLevels {
+		names[name] = spec
+	}
+	for name, spec := range after.loggerLevels {
+		names[name] = spec
+	}
+	for _, name := range sortedKeys(names) {
+		from, had := before.loggerLevels[name]
+		to, has := after.loggerLevels[name]
+		switch {
+		case !had:
+			changes = append(changes, fmt.Sprintf("logger %s level %s", name, to.Name))
+		case !has:
+			changes = append(changes, fmt.Sprintf("logger %s level %s -> global", name, from.Name))
+		case from.Name != to.Name:
+			changes = append(changes, fmt.Sprintf("logger %s level %s -> %s", name, from.Name, to.Name))
+		}
+	}
+
+	if before.bufferSize != after.bufferSize {
+		changes = append(changes, fmt.Sprintf("channel buffer size %d -> %d", before.bufferSize, after.bufferSize))
+	}
+	if (before.console != nil) != (after.console != nil) {
+		changes = append(changes, fmt.Sprintf("console output %s", enabledLabel(after.console != nil)))
+	}
+	if before.color != after.color {
+		changes = append(changes, fmt.Sprintf("color %s", enabledLabel(after.color)))
+	}
+	return changes
+}
+
+func describeSection(name string, wasNil, isNil bool) string {
+	switch {
+	case wasNil:
+		return name + " enabled"
+	case isNil:
+		return name + " disabled"
+	default:
+		return name + " changed"
+	}
+}
+
+func enabledLabel(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func levelOrAll(level string) string {
+	if level == "" {
+		return "ALL"
+	}
+	return level
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NewLogger returns the named logger configured by c: its level is the
+// global part of Level and its outputs receive only its own entries.
+// Console text output on stdout is provided by the package console output
+// and is not duplicated. Channels, sampling, redaction and collapsing are
+// process wide and are ignored; use Apply for those.
+func (c *FileConfig) NewLogger(name string) (*Logger, error) {
+	if name == "" {
+		return nil, fmt.Errorf("logger name must not be empty")
+	}
+	plan, err := c.compile()
+	if err != nil {
+		return nil, err
+	}
+	if err := SetLoggerLevel(name, plan.global); err != nil {
+		return nil, err
+	}
+
+	prefix := configSinkPrefix + name + "."
+	opened, err := openOutputs(plan.outputs, prefix, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range SinkNames() {
+		if _, ok := opened[existing]; !ok && strings.HasPrefix(existing, prefix) {
+			RemoveSink(existing)
+		}
+	}
+	for sinkName, sink := range opened {
+		AddSink(sinkName, sink)
+	}
+	return Named(name), nil
+}
+
+// openOutputs 打开所有输出，任一失败时关闭已打开的输出。
+// logger 非空时输出只接收该命名 logger 的条目
+func openOutputs(outputs []OutputConfig, prefix, logger string) (map[string]Sink, error) {
+	opened := make(map[string]Sink, len(outputs))
+	for _, o := range outputs {
+		sink, err := o.open()
+		if err != nil {
+			for _, s := range opened {
+				s.Close()
+			}
+			return nil, fmt.Errorf("output %q: %w", o.Name, err)
+		}
+		if o.Level != "" || logger != "" {
+			sink = &filteredSink{Sink: sink, level: o.Level, logger: logger}
+		}
+		opened[prefix+o.Name] = sink
+	}
+	return opened, nil
+}
+
+// writerSink 使用 Formatter 将条目写入 io.Writer
+type writerSink struct {
+	mu        sync.Mutex
+	w         io.Writer
+	closer    io.Closer
+	formatter Formatter
+}
+
+// WriteEntry 实现 Sink
+func (s *writerSink) WriteEntry(entry LogEntry) error {
+	data, err := s.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(data)
+	return err
+}
+
+// Close 实现 Sink
+func (s *writerSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// filteredSink 只转发达到 level 且属于 logger 的条目，空值不过滤
+type filteredSink struct {
+	Sink
+	level  string
+	logger string
+}
+
+// WriteEntry 实现 Sink
+func (s *filteredSink) WriteEntry(entry LogEntry) error {
+	if s.logger != "" && entry.Logger != s.logger {
+		return nil
+	}
+	if !levelAtLeast(entry.Level, s.level) {
+		return nil
+	}
+	return s.Sink.WriteEntry(entry)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
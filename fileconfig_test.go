@@ -0,0 +1,304 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// resetFileConfig 恢复默认配置并移除配置文件创建的 sink
+func resetFileConfig(t *testing.T) {
+	t.Cleanup(func() {
+		if err := (&FileConfig{}).Apply(); err != nil {
+			t.Errorf("reset config: %v", err)
+		}
+	})
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseConfigYAMLMatchesJSON(t *testing.T) {
+	yamlConfig := `
+# 服务日志配置
+product: api
+level: info,db=debug
+loggers:
+  http: warn
+outputs:
+  - type: console
+  - name: errors
+    type: file
+    path: "/var/log/api #1.log"   # 引号内的 # 不是注释
+    format: json
+    level: error
+channels:
+  - {name: audit}
+sampling:
+  interval: 2s
+  first: 10
+  thereafter: 100
+  rate_limits:
+    debug:
+      rate: 5
+      burst: 10
+redaction:
+  rules: [email, bearer]
+  deny_fields: []
+`
+	jsonConfig := `{
+		"product": "api",
+		"level": "info,db=debug",
+		"loggers": {"http": "warn"},
+		"outputs": [
+			{"type": "console"},
+			{"name": "errors", "type": "file", "path": "/var/log/api #1.log", "format": "json", "level": "error"}
+		],
+		"channels": [{"name": "audit"}],
+		"sampling": {"interval": "2s", "first": 10, "thereafter": 100, "rate_limits": {"debug": {"rate": 5, "burst": 10}}},
+		"redaction": {"rules": ["email", "bearer"], "deny_fields": []}
+	}`
+
+	// YAML 不支持流式映射
+	if _, err := ParseConfig([]byte(yamlConfig)); err == nil || !strings.Contains(err.Error(), "flow mappings") {
+		t.Fatalf("expected flow mapping error, got %v", err)
+	}
+	yamlConfig = strings.Replace(yamlConfig, "  - {name: audit}", "  - name: audit", 1)
+
+	fromYAML, err := ParseConfig([]byte(yamlConfig))
+	if err != nil {
+		t.Fatalf("ParseConfig(yaml): %v", err)
+	}
+	fromJSON, err := ParseConfig([]byte(jsonConfig))
+	if err != nil {
+		t.Fatalf("ParseConfig(json): %v", err)
+	}
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		a, _ := json.Marshal(fromYAML)
+		b, _ := json.Marshal(fromJSON)
+		t.Fatalf("yaml and json differ:\n%s\n%s", a, b)
+	}
+}
+
+func TestLoadConfigApply(t *testing.T) {
+	resetFileConfig(t)
+	defer RemoveLogChannel("config-audit")
+	defer SetProductName("")
+
+	dir := t.TempDir()
+	path := writeConfigFile(t, "logging.yaml", `
+product: cfgtest
+level: info
+loggers:
+  cfg-db: trace
+channel_buffer_size: 7
+outputs:
+  - name: all
+    type: file
+    path: `+filepath.Join(dir, "all.log")+`
+  - name: errors
+    type: rotating
+    path: `+filepath.Join(dir, "errors.log")+`
+    format: json
+    level: error
+    max_size: 1048576
+    max_backups: 2
+channels:
+  - name: config-audit
+    buffer_size: 3
+    level: warn
+redaction:
+  rules: []
+  patterns:
+    - pattern: 'token=\w+'
+      mask: token=***
+`)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if err := config.Apply(); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if got := GetLevel(); got != LevelInfo {
+		t.Errorf("GetLevel() = %s, want INFO", got)
+	}
+	if got := GetLoggerLevel("cfg-db"); got != LevelTrace {
+		t.Errorf("GetLoggerLevel(cfg-db) = %s, want TRACE", got)
+	}
+	if got := GetPrefix(); got != "cfgtest" {
+		t.Errorf("GetPrefix() = %q, want cfgtest", got)
+	}
+	if got := currentConfig().bufferSize; got != 7 {
+		t.Errorf("channel buffer size = %d, want 7", got)
+	}
+	if names := SinkNames(); !containsString(names, "config.all") || !containsString(names, "config.errors") {
+		t.Errorf("SinkNames() = %v, want config.all and config.errors", names)
+	}
+
+	Debug("filtered out")
+	Info("hello token=abc123")
+	Named("cfg-db").Trace("db trace")
+	Error("boom")
+
+	all, _ := os.ReadFile(filepath.Join(dir, "all.log"))
+	for _, want := range []string{"[cfgtest] [INFO] hello token=***", "[TRACE] [cfg-db] db trace", "[ERROR] boom"} {
+		if !strings.Contains(string(all), want) {
+			t.Errorf("all.log missing %q:\n%s", want, all)
+		}
+	}
+	if strings.Contains(string(all), "filtered out") {
+		t.Errorf("all.log contains DEBUG entry:\n%s", all)
+	}
+
+	errors, _ := os.ReadFile(filepath.Join(dir, "errors.log"))
+	lines := strings.Split(strings.TrimSpace(string(errors)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("errors.log has %d lines, want 1:\n%s", len(lines), errors)
+	}
+	var entry LogEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil || entry.Message != "boom" {
+		t.Errorf("errors.log entry = %+v, %v", entry, err)
+	}
+
+	for _, stats := range GetChannelStats() {
+		if stats.Name == "config-audit" {
+			if stats.BufferSize != 3 || stats.Level != LevelWarn || stats.Sent != 1 {
+				t.Errorf("config-audit stats = %+v", stats)
+			}
+			return
+		}
+	}
+	t.Error("channel config-audit was not registered")
+}
+
+func TestApplyRemovesStaleOutputs(t *testing.T) {
+	resetFileConfig(t)
+
+	path := filepath.Join(t.TempDir(), "out.log")
+	first := &FileConfig{Outputs: []OutputConfig{{Name: "stale", Type: "file", Path: path}}}
+	if err := first.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if !containsString(SinkNames(), "config.stale") {
+		t.Fatalf("SinkNames() = %v", SinkNames())
+	}
+
+	if err := (&FileConfig{}).Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if containsString(SinkNames(), "config.stale") {
+		t.Fatalf("stale output still registered: %v", SinkNames())
+	}
+}
+
+func TestApplyFailureKeepsConfiguration(t *testing.T) {
+	resetFileConfig(t)
+
+	before := currentConfig()
+	config := &FileConfig{
+		Level:   LevelError,
+		Outputs: []OutputConfig{{Type: "file", Path: filepath.Join(t.TempDir(), "missing", "\x00", "x.log")}},
+	}
+	if err := config.Apply(); err == nil {
+		t.Fatal("Apply with unopenable output should fail")
+	}
+	if currentConfig() != before {
+		t.Fatal("failed Apply changed the configuration")
+	}
+}
+
+func TestConfigNewLogger(t *testing.T) {
+	defer SetLoggerLevel("cfg-instance", "")
+	defer RemoveSink("config.cfg-instance.file")
+
+	path := filepath.Join(t.TempDir(), "instance.log")
+	config, err := ParseConfig([]byte(`
+level: warn
+outputs:
+  - type: console
+  - type: file
+    path: ` + path + `
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := config.NewLogger("cfg-instance")
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	if got := l.GetLevel(); got != LevelWarn {
+		t.Errorf("level = %s, want WARN", got)
+	}
+
+	l.Info("below level")
+	l.Warn("instance warning")
+	Warn("package warning")
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "instance warning") {
+		t.Errorf("instance.log missing logger entry:\n%s", data)
+	}
+	if strings.Contains(string(data), "package warning") || strings.Contains(string(data), "below level") {
+		t.Errorf("instance.log contains foreign or filtered entries:\n%s", data)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{`{"level": "loud"}`, `unknown level "loud"`},
+		{`{"lvl": "info"}`, `unknown field "lvl"`},
+		{`{"outputs": [{"type": "carrier-pigeon"}]}`, `unknown output type`},
+		{`{"outputs": [{"type": "file"}]}`, `requires a path`},
+		{`{"outputs": [{"type": "console"}, {"type": "console", "stream": "stderr"}]}`, `duplicate output name`},
+		{`{"outputs": [{"type": "syslog", "format": "json"}]}`, `not supported by syslog`},
+		{`{"channels": [{"name": "a", "policy": "block"}]}`, `unsupported policy`},
+		{`{"sampling": {"interval": "soon"}}`, `sampling:`},
+		{`{"redaction": {"rules": ["ssn"]}}`, `unknown rule "ssn"`},
+		{`{"redaction": {"patterns": [{"pattern": "("}]}}`, `patterns[0]`},
+		{"outputs:\n  - type: file\n     path: x", `unexpected indentation`},
+		{"level: info\nlevel: warn", `duplicate key`},
+		{`{"outputs": [{"type": "network", "address": "x:1"}]}`, `unsupported network ""`},
+		{`{"outputs": [{"type": "network", "network": "sctp", "address": "x:1"}]}`, `unsupported network "sctp"`},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseConfig([]byte(tt.config))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseConfig(%q) error = %v, want %q", tt.config, err, tt.want)
+		}
+	}
+}
+
+func TestOutputFormatDefaults(t *testing.T) {
+	tests := []struct {
+		output OutputConfig
+		want   string
+	}{
+		{OutputConfig{Type: "console"}, "text"},
+		{OutputConfig{Type: "network", Network: "tcp", Address: "x:1"}, "json"},
+		{OutputConfig{Type: "otlp", Endpoint: "http://collector:4318"}, "protobuf"},
+		{OutputConfig{Type: "otlp", Endpoint: "http://collector:4318", Format: "JSON"}, "json"},
+	}
+	for _, tt := range tests {
+		o, err := tt.output.normalize()
+		if err != nil || o.Format != tt.want {
+			t.Errorf("normalize(%+v) format = %q, %v; want %q", tt.output, o.Format, err, tt.want)
+		}
+	}
+}
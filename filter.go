@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// EntryFilter selects log entries by level, prefix, logger name and message text
+// Empty fields match everything
+type EntryFilter struct {
+	Level  string // 最低级别
+	Prefix string // 与 LogEntry.Prefix 完全匹配
+	Logger string // 与 LogEntry.Logger 完全匹配
+	Text   string // LogEntry.Message 包含的子串
+}
+
+// Match 判断条目是否满足过滤条件
+func (f EntryFilter) Match(entry LogEntry) bool {
+	if !levelAtLeast(entry.Level, f.Level) {
+		return false
+	}
+	if f.Prefix != "" && entry.Prefix != f.Prefix {
+		return false
+	}
+	if f.Logger != "" && entry.Logger != f.Logger {
+		return false
+	}
+	if f.Text != "" && !strings.Contains(entry.Message, f.Text) {
+		return false
+	}
+	return true
+}
+
+// filterFromQuery 从 URL 查询参数 level、prefix、logger、text 构造过滤器
+func filterFromQuery(query url.Values) (EntryFilter, error) {
+	filter := EntryFilter{
+		Prefix: query.Get("prefix"),
+		Logger: query.Get("logger"),
+		Text:   query.Get("text"),
+	}
+
+	if level := query.Get("level"); level != "" {
+		spec, ok := GetLevelSpec(level)
+		if !ok {
+			return EntryFilter{}, fmt.Errorf("unknown level %q", level)
+		}
+		filter.Level = spec.Name
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"encoding/json"
+)
+
+// TextTimeLayout 是文本格式的时间戳布局，与 log.Ldate|log.Ltime 一致
+const TextTimeLayout = "2006/01/02 15:04:05"
+
+// Formatter encodes a log entry for an output
+type Formatter interface {
+	Format(entry LogEntry) ([]byte, error)
+}
+
+// TextFormatter formats entries exactly like the console output:
+//
+//	2006/01/02 15:04:05 [Product] [LEVEL] [logger] message key=value
+//
+// followed by the stack trace for levels with StackPrint.
+type TextFormatter struct{}
+
+// Format 实现 Formatter
+func (TextFormatter) Format(entry LogEntry) ([]byte, error) {
+	buf := make([]byte, 0, 64+len(entry.Message))
+	buf = entry.Timestamp.AppendFormat(buf, TextTimeLayout)
+	buf = append(buf, ' ')
+	if entry.Prefix != "" {
+		buf = append(buf, '[')
+		buf = append(buf, entry.Prefix...)
+		buf = append(buf, "] "...)
+	}
+	buf = append(buf, '[')
+	buf = append(buf, entry.Level...)
+	buf = append(buf, "] "...)
+	if entry.Logger != "" {
+		buf = append(buf, '[')
+		buf = append(buf, entry.Logger...)
+		buf = append(buf, "] "...)
+	}
+	buf = append(buf, stripNewline(entry.Message)...)
+	buf = append(buf, formatFields(entry.Fields)...)
+	buf = append(buf, '\n')
+	if resolveLevel(entry.Level).Stack == StackPrint {
+		buf = append(buf, entry.StackTrace...)
+	}
+	return buf, nil
+}
+
+// JSONFormatter formats entries as one JSON object per line
+type JSONFormatter struct{}
+
+// Format 实现 Formatter
+func (JSONFormatter) Format(entry LogEntry) ([]byte, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,499 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchEncoder encodes a batch of entries into an HTTP request body
+type BatchEncoder interface {
+	ContentType() string
+	Encode(entries []LogEntry) ([]byte, error)
+}
+
+// ResponseChecker may be implemented by a BatchEncoder whose endpoint reports
+// rejected entries in a successful response, as the Elasticsearch bulk API
+// does. CheckResponse reads the body and returns the number of rejected
+// entries and the reason of the first rejection. An error with no rejected
+// entries means the body could not be read; the batch then counts as sent.
+type ResponseChecker interface {
+	CheckResponse(body io.Reader) (rejected int, err error)
+}
+
+// maxResponseBytes 是 ResponseChecker 读取的响应体上限
+const maxResponseBytes = 16 << 20
+
+// NDJSONEncoder encodes one JSON object per line
+type NDJSONEncoder struct{}
+
+// ContentType 实现 BatchEncoder
+func (NDJSONEncoder) ContentType() string {
+	return "application/x-ndjson"
+}
+
+// Encode 实现 BatchEncoder
+func (NDJSONEncoder) Encode(entries []LogEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// LokiEncoder encodes a Loki push API request (POST /loki/api/v1/push).
+// Entries are grouped into streams by level, prefix and logger, which become
+// the labels level, app and logger next to the static Labels.
+type LokiEncoder struct {
+	Labels map[string]string
+}
+
+// ContentType 实现 BatchEncoder
+func (LokiEncoder) ContentType() string {
+	return "application/json"
+}
+
+// lokiStream 是 Loki push 请求中的一个 stream
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// Encode 实现 BatchEncoder
+func (e LokiEncoder) Encode(entries []LogEntry) ([]byte, error) {
+	var streams []*lokiStream
+	index := make(map[string]*lokiStream)
+
+	for _, entry := range entries {
+		key := entry.Level + "\x00" + entry.Prefix + "\x00" + entry.Logger
+		stream, ok := index[key]
+		if !ok {
+			labels := make(map[string]string, len(e.Labels)+3)
+			for k, v := range e.Labels {
+				labels[k] = v
+			}
+			labels["level"] = strings.ToLower(entry.Level)
+			if entry.Prefix != "" {
+				labels["app"] = entry.Prefix
+			}
+			if entry.Logger != "" {
+				labels["logger"] = entry.Logger
+			}
+
+			stream = &lokiStream{Stream: labels}
+			index[key] = stream
+			streams = append(streams, stream)
+		}
+
+		line := stripNewline(entry.Message) + formatFields(entry.Fields)
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(entry.Timestamp.UnixNano(), 10),
+			line,
+		})
+	}
+
+	return json.Marshal(map[string]any{"streams": streams})
+}
+
+// ElasticsearchEncoder encodes an Elasticsearch bulk API request (POST /_bulk)
+type ElasticsearchEncoder struct {
+	Index string // 目标索引
+}
+
+// ContentType 实现 BatchEncoder
+func (ElasticsearchEncoder) ContentType() string {
+	return "application/x-ndjson"
+}
+
+// Encode 实现 BatchEncoder
+func (e ElasticsearchEncoder) Encode(entries []LogEntry) ([]byte, error) {
+	action, err := json.Marshal(map[string]any{"index": map[string]string{"_index": e.Index}})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(action)
+		buf.WriteByte('\n')
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// bulkResponse 是 Elasticsearch bulk API 响应中用到的部分
+type bulkResponse struct {
+	Errors bool                        `json:"errors"`
+	Items  []map[string]bulkItemResult `json:"items"`
+}
+
+// bulkItemResult 是单个文档的处理结果
+type bulkItemResult struct {
+	Status int `json:"status"`
+	Error  *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// CheckResponse 实现 ResponseChecker，统计 bulk 响应中被拒绝的文档
+func (ElasticsearchEncoder) CheckResponse(body io.Reader) (int, error) {
+	var resp bulkResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return 0, fmt.Errorf("decode bulk response: %w", err)
+	}
+	if !resp.Errors {
+		return 0, nil
+	}
+
+	rejected := 0
+	var first error
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Error == nil && result.Status < 300 {
+				continue
+			}
+			rejected++
+			if first == nil && result.Error != nil {
+				first = fmt.Errorf("%s: %s", result.Error.Type, result.Error.Reason)
+			} else if first == nil {
+				first = fmt.Errorf("status %d", result.Status)
+			}
+		}
+	}
+	if rejected == 0 {
+		return 0, fmt.Errorf("bulk response reports errors but no failed items")
+	}
+	return rejected, fmt.Errorf("elasticsearch rejected %d entries: %w", rejected, first)
+}
+
+// HTTPConfig 配置 HTTP 批量发送 sink
+type HTTPConfig struct {
+	URL     string
+	Encoder BatchEncoder // 默认 NDJSONEncoder
+	Headers http.Header  // 额外请求头，例如认证信息
+	Client  *http.Client // 默认 http.Client{Timeout: 10s}
+	Gzip    bool         // 使用 gzip 压缩请求体
+
+	BatchSize     int           // 每批最多条目数，默认 100
+	BatchBytes    int           // 每批估算字节数上限，默认 1MB
+	FlushInterval time.Duration // 定时发送间隔，默认 1s
+	QueueSize     int           // 等待发送的条目上限，默认 10000，满时丢弃最旧的条目
+
+	MaxRetries int           // 失败重试次数，默认 3，负数表示不重试
+	MinBackoff time.Duration // 首次重试等待时间，默认 200ms
+	MaxBackoff time.Duration // 重试等待时间上限，默认 10s
+}
+
+// HTTPSinkStats 描述 HTTP sink 的状态
+type HTTPSinkStats struct {
+	Pending  int    // 等待发送的条目数
+	Sent     uint64 // 已成功发送的条目数
+	Dropped  uint64 // 因队列满、重试耗尽或被服务端拒绝而丢弃的条目数
+	Requests uint64 // 已发出的请求数（含重试）
+}
+
+// HTTPSink batches entries and POSTs them to an HTTP ingestion endpoint.
+// A batch is sent when it reaches BatchSize entries or BatchBytes, every
+// FlushInterval, on Flush and on Close. Network errors, 429 and 5xx responses
+// are retried with exponential backoff; other responses drop the batch.
+// Entries that a ResponseChecker reports as rejected are dropped, not retried.
+type HTTPSink struct {
+	config HTTPConfig
+
+	mu           sync.Mutex
+	pending      []LogEntry
+	pendingBytes int
+
+	sendMu sync.Mutex // 保证批次按顺序发送
+
+	wake chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	sent      atomic.Uint64
+	dropped   atomic.Uint64
+	requests  atomic.Uint64
+	closeOnce sync.Once
+}
+
+// NewHTTPSink creates the sink and starts the background flusher
+func NewHTTPSink(config HTTPConfig) (*HTTPSink, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("http sink requires a URL")
+	}
+	if config.Encoder == nil {
+		config.Encoder = NDJSONEncoder{}
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.BatchBytes <= 0 {
+		config.BatchBytes = 1 << 20
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10000
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	} else if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = 200 * time.Millisecond
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = 10 * time.Second
+	}
+
+	s := &HTTPSink{
+		config: config,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	return s, nil
+}
+
+// WriteEntry 将条目加入队列，达到批次上限时唤醒后台发送
+func (s *HTTPSink) WriteEntry(entry LogEntry) error {
+	s.mu.Lock()
+	if len(s.pending) >= s.config.QueueSize {
+		s.pendingBytes -= estimateEntrySize(s.pending[0])
+		s.pending = s.pending[1:]
+		s.dropped.Add(1)
+	}
+	s.pending = append(s.pending, entry)
+	s.pendingBytes += estimateEntrySize(entry)
+	full := len(s.pending) >= s.config.BatchSize || s.pendingBytes >= s.config.BatchBytes
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Flush sends all queued entries and returns the last delivery error
+func (s *HTTPSink) Flush() error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	var lastErr error
+	for {
+		batch := s.takeBatch()
+		if len(batch) == 0 {
+			return lastErr
+		}
+		if err := s.send(batch); err != nil {
+			lastErr = err
+		}
+	}
+}
+
+// Close flushes queued entries and stops the background flusher
+func (s *HTTPSink) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+		err = s.Flush()
+	})
+	return err
+}
+
+// Stats 返回当前状态
+func (s *HTTPSink) Stats() HTTPSinkStats {
+	s.mu.Lock()
+	pending := len(s.pending)
+	s.mu.Unlock()
+
+	return HTTPSinkStats{
+		Pending:  pending,
+		Sent:     s.sent.Load(),
+		Dropped:  s.dropped.Load(),
+		Requests: s.requests.Load(),
+	}
+}
+
+// run 是后台发送循环
+func (s *HTTPSink) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.Flush()
+		case <-s.wake:
+			s.sendMu.Lock()
+			for s.batchReady() {
+				s.send(s.takeBatch())
+			}
+			s.sendMu.Unlock()
+		}
+	}
+}
+
+// batchReady 判断队列是否已达到一个完整批次
+func (s *HTTPSink) batchReady() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.pending) >= s.config.BatchSize || s.pendingBytes >= s.config.BatchBytes
+}
+
+// takeBatch 从队列头部取出一个批次
+func (s *HTTPSink) takeBatch() []LogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, size := 0, 0
+	for n < len(s.pending) && n < s.config.BatchSize {
+		size += estimateEntrySize(s.pending[n])
+		n++
+		if size >= s.config.BatchBytes {
+			break
+		}
+	}
+
+	batch := make([]LogEntry, n)
+	copy(batch, s.pending[:n])
+	s.pending = s.pending[n:]
+	s.pendingBytes -= size
+	return batch
+}
+
+// send 编码并发送一个批次，按配置重试
+func (s *HTTPSink) send(batch []LogEntry) error {
+	body, err := s.config.Encoder.Encode(batch)
+	if err != nil {
+		s.dropped.Add(uint64(len(batch)))
+		return err
+	}
+
+	if s.config.Gzip {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(body)
+		zw.Close()
+		body = buf.Bytes()
+	}
+
+	backoff := s.config.MinBackoff
+	for attempt := 0; ; attempt++ {
+		retry, rejected, err := s.post(body)
+		if err == nil {
+			s.sent.Add(uint64(len(batch)))
+			return nil
+		}
+		if rejected > 0 {
+			s.sent.Add(uint64(len(batch) - rejected))
+			s.dropped.Add(uint64(rejected))
+			warnf("Log HTTP sink: %v", err)
+			return err
+		}
+
+		if !retry || attempt >= s.config.MaxRetries {
+			s.dropped.Add(uint64(len(batch)))
+			warnf("Log HTTP sink dropped %d entries: %v", len(batch), err)
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-s.done:
+			// 关闭时不再等待退避，立即重试剩余次数
+		}
+		backoff *= 2
+		if backoff > s.config.MaxBackoff {
+			backoff = s.config.MaxBackoff
+		}
+	}
+}
+
+// post 发送一次请求，返回错误是否可重试；成功的响应中被拒绝的条目数由 ResponseChecker 报告
+func (s *HTTPSink) post(body []byte) (bool, int, error) {
+	s.requests.Add(1)
+
+	req, err := http.NewRequest(http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, 0, err
+	}
+	for key, values := range s.config.Headers {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	req.Header.Set("Content-Type", s.config.Encoder.ContentType())
+	if s.config.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return true, 0, err
+	}
+	defer func() {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		checker, ok := s.config.Encoder.(ResponseChecker)
+		if !ok {
+			return false, 0, nil
+		}
+		rejected, err := checker.CheckResponse(io.LimitReader(resp.Body, maxResponseBytes))
+		if err != nil && rejected == 0 {
+			warnf("Log HTTP sink could not check the response: %v", err)
+			return false, 0, nil
+		}
+		return false, rejected, err
+	}
+
+	err = fmt.Errorf("unexpected status %s", resp.Status)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, 0, err
+}
+
+// estimateEntrySize 估算条目编码后的字节数
+func estimateEntrySize(entry LogEntry) int {
+	size := 128 + len(entry.Message) + len(entry.StackTrace) + len(entry.Prefix) + len(entry.Logger)
+	for _, f := range entry.Fields {
+		size += len(f.Key) + 16
+	}
+	return size
+}
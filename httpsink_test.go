@@ -0,0 +1,255 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ingestServer 记录收到的请求体（已解压）
+type ingestServer struct {
+	mu       sync.Mutex
+	bodies   []string
+	headers  []http.Header
+	failures int // 前 failures 次请求返回 503
+}
+
+func (s *ingestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var reader io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reader = zr
+	}
+	body, _ := io.ReadAll(reader)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+		return
+	}
+	s.bodies = append(s.bodies, string(body))
+	s.headers = append(s.headers, r.Header.Clone())
+}
+
+func (s *ingestServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+func TestHTTPSinkBatchesByCount(t *testing.T) {
+	ingest := &ingestServer{}
+	server := httptest.NewServer(ingest)
+	defer server.Close()
+
+	sink, err := NewHTTPSink(HTTPConfig{
+		URL:           server.URL,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		Gzip:          true,
+		Headers:       http.Header{"Authorization": {"Bearer t"}},
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPSink failed: %v", err)
+	}
+
+	for _, msg := range []string{"a", "b", "c", "d", "e"} {
+		sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelInfo, Message: msg})
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(ingest.received()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 2 full batches, got %d", len(ingest.received()))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 剩余的不满一批，Close 时发送
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	bodies := ingest.received()
+	if len(bodies) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(bodies))
+	}
+
+	var messages []string
+	for _, body := range bodies {
+		scanner := bufio.NewScanner(strings.NewReader(body))
+		for scanner.Scan() {
+			var entry LogEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				t.Fatalf("Invalid NDJSON line: %v", err)
+			}
+			messages = append(messages, entry.Message)
+		}
+	}
+	if strings.Join(messages, "") != "abcde" {
+		t.Errorf("Unexpected messages order: %v", messages)
+	}
+
+	h := ingest.headers[0]
+	if h.Get("Content-Type") != "application/x-ndjson" || h.Get("Authorization") != "Bearer t" {
+		t.Errorf("Unexpected headers: %v", h)
+	}
+
+	if stats := sink.Stats(); stats.Sent != 5 || stats.Pending != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestHTTPSinkRetries(t *testing.T) {
+	ingest := &ingestServer{failures: 2}
+	server := httptest.NewServer(ingest)
+	defer server.Close()
+
+	sink, err := NewHTTPSink(HTTPConfig{
+		URL:           server.URL,
+		FlushInterval: time.Hour,
+		MinBackoff:    time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelWarn, Message: "retry me"})
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	stats := sink.Stats()
+	if stats.Requests != 3 || stats.Sent != 1 || len(ingest.received()) != 1 {
+		t.Errorf("Unexpected stats after retries: %+v", stats)
+	}
+
+	ingest.mu.Lock()
+	ingest.failures = 10
+	ingest.mu.Unlock()
+	sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelWarn, Message: "give up"})
+	if err := sink.Flush(); err == nil {
+		t.Error("Expected error after retries are exhausted")
+	}
+	if stats := sink.Stats(); stats.Dropped != 1 {
+		t.Errorf("Expected 1 dropped entry, got %+v", stats)
+	}
+}
+
+func TestHTTPSinkInterval(t *testing.T) {
+	ingest := &ingestServer{}
+	server := httptest.NewServer(ingest)
+	defer server.Close()
+
+	sink, err := NewHTTPSink(HTTPConfig{URL: server.URL, FlushInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewHTTPSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelInfo, Message: "tick"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(ingest.received()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Entry not sent by interval flush")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLokiEncoder(t *testing.T) {
+	timestamp := time.Unix(1700000000, 5)
+	body, err := LokiEncoder{Labels: map[string]string{"env": "prod"}}.Encode([]LogEntry{
+		{Timestamp: timestamp, Level: LevelInfo, Prefix: "App", Message: "one"},
+		{Timestamp: timestamp, Level: LevelError, Prefix: "App", Message: "two", Fields: []Field{F("k", "v")}},
+		{Timestamp: timestamp, Level: LevelInfo, Prefix: "App", Message: "three"},
+	})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	want := `{"streams":[` +
+		`{"stream":{"app":"App","env":"prod","level":"info"},"values":[["1700000000000000005","one"],["1700000000000000005","three"]]},` +
+		`{"stream":{"app":"App","env":"prod","level":"error"},"values":[["1700000000000000005","two k=v"]]}]}`
+	if string(body) != want {
+		t.Errorf("Unexpected Loki body:\n got: %s\nwant: %s", body, want)
+	}
+}
+
+func TestElasticsearchEncoder(t *testing.T) {
+	body, err := ElasticsearchEncoder{Index: "logs"}.Encode([]LogEntry{
+		{Timestamp: time.Now(), Level: LevelInfo, Message: "one"},
+		{Timestamp: time.Now(), Level: LevelWarn, Message: "two"},
+	})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %d: %s", len(lines), body)
+	}
+	if lines[0] != `{"index":{"_index":"logs"}}` || !strings.Contains(lines[3], `"msg":"two"`) {
+		t.Errorf("Unexpected bulk body: %s", body)
+	}
+}
+
+func TestHTTPSinkElasticsearchRejections(t *testing.T) {
+	response := `{"took":3,"errors":false,"items":[]}`
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		io.WriteString(w, response)
+	}))
+	defer server.Close()
+
+	sink, err := NewHTTPSink(HTTPConfig{
+		URL:           server.URL,
+		Encoder:       ElasticsearchEncoder{Index: "logs"},
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelInfo, Message: "accepted"})
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	mu.Lock()
+	response = `{"took":3,"errors":true,"items":[` +
+		`{"index":{"status":201}},` +
+		`{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [n]"}}},` +
+		`{"index":{"status":201}}]}`
+	mu.Unlock()
+	for _, msg := range []string{"one", "two", "three"} {
+		sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelInfo, Message: msg})
+	}
+	err = sink.Flush()
+	if err == nil || !strings.Contains(err.Error(), "rejected 1 entries: mapper_parsing_exception") {
+		t.Errorf("Expected rejection error, got %v", err)
+	}
+	if stats := sink.Stats(); stats.Sent != 3 || stats.Dropped != 1 || stats.Requests != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,228 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultJournalSocket 是 journald 原生协议的默认 socket 路径
+const DefaultJournalSocket = "/run/systemd/journal/socket"
+
+const (
+	journaldWriteTimeout = time.Second
+	journaldMinBackoff   = 100 * time.Millisecond
+	journaldMaxBackoff   = 30 * time.Second
+)
+
+// JournaldConfig 配置 journald sink
+type JournaldConfig struct {
+	SocketPath string // journald socket 路径，默认 DefaultJournalSocket
+	Identifier string // SYSLOG_IDENTIFIER，默认使用 SetProductName 设置的名称
+}
+
+// JournaldSinkStats 描述 journald sink 的状态
+type JournaldSinkStats struct {
+	Connected bool
+	Dropped   uint64 // 连接断开期间丢弃的条目数
+}
+
+// JournaldSink writes entries to journald using its native datagram protocol.
+// When journald is unreachable, entries are dropped and counted; reconnects
+// are attempted with exponential backoff instead of on every entry.
+type JournaldSink struct {
+	config   JournaldConfig
+	mu       sync.Mutex
+	conn     *net.UnixConn // 断开期间为 nil
+	backoff  time.Duration // 当前重连间隔
+	nextDial time.Time     // 断开后下次允许重连的时间
+	closed   bool
+	dropped  atomic.Uint64
+}
+
+// NewJournaldSink connects to the journald socket described by config.
+// Each entry becomes one journal record with MESSAGE, PRIORITY,
+// SYSLOG_IDENTIFIER, CODE_FILE, CODE_LINE, CODE_FUNC and one field per
+// structured field (upper-cased, invalid characters replaced by '_').
+// It returns an error on platforms without unix sockets.
+func NewJournaldSink(config JournaldConfig) (*JournaldSink, error) {
+	if err := journaldSupported(); err != nil {
+		return nil, err
+	}
+	if config.SocketPath == "" {
+		config.SocketPath = DefaultJournalSocket
+	}
+
+	s := &JournaldSink{config: config}
+	if err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// connect 建立连接，调用方需持有锁或处于初始化阶段
+func (s *JournaldSink) connect() error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: s.config.SocketPath, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("journald connect failed: %w", err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// WriteEntry 编码并发送条目，超过数据报大小限制的条目通过临时文件描述符发送。
+// 连接断开后只在退避时间到达时重连，其间的条目被丢弃并计数；关闭后返回 os.ErrClosed
+func (s *JournaldSink) WriteEntry(entry LogEntry) error {
+	data := s.encode(entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return os.ErrClosed
+	}
+	if s.conn == nil {
+		if time.Now().Before(s.nextDial) {
+			s.dropped.Add(1)
+			return nil
+		}
+		if err := s.connect(); err != nil {
+			s.disconnected()
+			return err
+		}
+		s.backoff = 0
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(journaldWriteTimeout))
+	_, err := s.conn.Write(data)
+	if err == nil {
+		return nil
+	}
+	if isMessageTooLarge(err) {
+		return sendJournalViaFile(s.conn, data)
+	}
+
+	s.conn.Close()
+	s.conn = nil
+	s.disconnected()
+	return fmt.Errorf("%w; dropping entries until reconnected", err)
+}
+
+// disconnected 记录丢弃的条目并推迟下次重连，调用方需持有锁
+func (s *JournaldSink) disconnected() {
+	s.dropped.Add(1)
+	s.backoff *= 2
+	if s.backoff < journaldMinBackoff {
+		s.backoff = journaldMinBackoff
+	}
+	if s.backoff > journaldMaxBackoff {
+		s.backoff = journaldMaxBackoff
+	}
+	s.nextDial = time.Now().Add(s.backoff)
+}
+
+// Stats 返回连接状态和丢弃计数
+func (s *JournaldSink) Stats() JournaldSinkStats {
+	s.mu.Lock()
+	connected := s.conn != nil
+	s.mu.Unlock()
+	return JournaldSinkStats{Connected: connected, Dropped: s.dropped.Load()}
+}
+
+// Close 关闭连接，之后不再重连
+func (s *JournaldSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// encode 按 journald 原生协议编码条目
+func (s *JournaldSink) encode(entry LogEntry) []byte {
+	var buf bytes.Buffer
+
+	identifier := s.config.Identifier
+	if identifier == "" {
+		identifier = entry.Prefix
+	}
+
+	writeJournalField(&buf, "MESSAGE", stripNewline(entry.Message))
+	writeJournalField(&buf, "PRIORITY", strconv.Itoa(syslogSeverity(entry.Level)))
+	writeJournalField(&buf, "LOG_LEVEL", entry.Level)
+	if identifier != "" {
+		writeJournalField(&buf, "SYSLOG_IDENTIFIER", identifier)
+	}
+	if entry.Logger != "" {
+		writeJournalField(&buf, "LOGGER", entry.Logger)
+	}
+	if entry.File != "" {
+		writeJournalField(&buf, "CODE_FILE", entry.File)
+		writeJournalField(&buf, "CODE_LINE", strconv.Itoa(entry.Line))
+	}
+	if entry.Function != "" {
+		writeJournalField(&buf, "CODE_FUNC", entry.Function)
+	}
+	if resolveLevel(entry.Level).Stack == StackPrint && len(entry.StackTrace) > 0 {
+		writeJournalField(&buf, "STACK_TRACE", string(entry.StackTrace))
+	}
+	for _, f := range entry.Fields {
+		writeJournalField(&buf, journalFieldName(f.Key), f.String())
+	}
+
+	return buf.Bytes()
+}
+
+// writeJournalField 写入一个字段，含换行的值使用长度前缀的二进制格式
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalFieldName 将字段名转换为合法的 journal 字段名：
+// 仅包含大写字母、数字和下划线，不以下划线或数字开头，最长 64 字节
+func journalFieldName(key string) string {
+	b := make([]byte, 0, len(key))
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			c -= 'a' - 'A'
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		default:
+			c = '_'
+		}
+		b = append(b, c)
+	}
+
+	name := strings.TrimLeft(string(b), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "FIELD_" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package logger
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+func journaldSupported() error {
+	return fmt.Errorf("journald is not supported on %s", runtime.GOOS)
+}
+
+func isMessageTooLarge(err error) bool {
+	return false
+}
+
+func sendJournalViaFile(conn *net.UnixConn, data []byte) error {
+	return fmt.Errorf("journald is not supported on %s", runtime.GOOS)
+}
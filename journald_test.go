@@ -0,0 +1,227 @@
+//go:build unix
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// listenJournal 在临时目录创建一个模拟 journald 的 unixgram socket
+func listenJournal(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, path
+}
+
+// parseJournal 解析 journald 原生协议数据报
+func parseJournal(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		nl := bytes.IndexByte(data, '\n')
+		if nl < 0 {
+			t.Fatalf("Truncated record: %q", data)
+		}
+		line := string(data[:nl])
+		data = data[nl+1:]
+
+		if name, value, ok := strings.Cut(line, "="); ok {
+			fields[name] = value
+			continue
+		}
+
+		size := binary.LittleEndian.Uint64(data[:8])
+		fields[line] = string(data[8 : 8+size])
+		data = data[8+size+1:]
+	}
+	return fields
+}
+
+func TestJournaldSinkFields(t *testing.T) {
+	server, path := listenJournal(t)
+
+	sink, err := NewJournaldSink(JournaldConfig{SocketPath: path})
+	if err != nil {
+		t.Fatalf("NewJournaldSink failed: %v", err)
+	}
+	AddSink("journald-test", sink)
+	defer RemoveSink("journald-test")
+
+	SetProductName("JournalApp")
+	defer SetProductName("")
+
+	Named("db").With(F("request-id", "r1"), F("2nd", "x"), F("sql", "SELECT 1\nFROM t")).Error("query failed")
+
+	buf := make([]byte, 1<<16)
+	server.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := server.Read(buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	fields := parseJournal(t, buf[:n])
+	expected := map[string]string{
+		"MESSAGE":           "query failed",
+		"PRIORITY":          "3",
+		"SYSLOG_IDENTIFIER": "JournalApp",
+		"LOGGER":            "db",
+		"REQUEST_ID":        "r1",
+		"FIELD_2ND":         "x",
+		"SQL":               "SELECT 1\nFROM t",
+	}
+	for name, want := range expected {
+		if fields[name] != want {
+			t.Errorf("%s: expected %q, got %q", name, want, fields[name])
+		}
+	}
+
+	if !strings.HasSuffix(fields["CODE_FILE"], "journald_test.go") || fields["CODE_LINE"] == "" {
+		t.Errorf("Unexpected code location: %s:%s", fields["CODE_FILE"], fields["CODE_LINE"])
+	}
+	if !strings.HasSuffix(fields["CODE_FUNC"], "TestJournaldSinkFields") {
+		t.Errorf("Unexpected CODE_FUNC %q", fields["CODE_FUNC"])
+	}
+	if !strings.Contains(fields["STACK_TRACE"], "goroutine") {
+		t.Error("ERROR entry should carry STACK_TRACE")
+	}
+}
+
+func TestJournaldSinkLargeEntryViaFile(t *testing.T) {
+	server, path := listenJournal(t)
+
+	sink, err := NewJournaldSink(JournaldConfig{SocketPath: path, Identifier: "big"})
+	if err != nil {
+		t.Fatalf("NewJournaldSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	message := strings.Repeat("x", 4<<20)
+	if err := sink.WriteEntry(LogEntry{Level: LevelInfo, Message: message}); err != nil {
+		t.Fatalf("WriteEntry failed: %v", err)
+	}
+
+	oob := make([]byte, syscall.CmsgSpace(4))
+	server.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, oobn, _, _, err := server.ReadMsgUnix(nil, oob)
+	if err != nil {
+		t.Fatalf("ReadMsgUnix failed: %v", err)
+	}
+
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("Expected one control message, got %d (%v)", len(msgs), err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("Expected one fd, got %v (%v)", fds, err)
+	}
+
+	file := os.NewFile(uintptr(fds[0]), "journal")
+	defer file.Close()
+	file.Seek(0, io.SeekStart)
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	fields := parseJournal(t, data)
+	if fields["MESSAGE"] != message || fields["SYSLOG_IDENTIFIER"] != "big" {
+		t.Errorf("Unexpected fields in large entry (message length %d)", len(fields["MESSAGE"]))
+	}
+}
+
+func TestJournalFieldName(t *testing.T) {
+	cases := map[string]string{
+		"user_id":  "USER_ID",
+		"_private": "PRIVATE",
+		"a.b-c":    "A_B_C",
+		"9lives":   "FIELD_9LIVES",
+		"":         "FIELD_",
+	}
+	for key, want := range cases {
+		if got := journalFieldName(key); got != want {
+			t.Errorf("%q: expected %q, got %q", key, want, got)
+		}
+	}
+}
+
+func TestJournaldSinkReconnectBackoff(t *testing.T) {
+	server, path := listenJournal(t)
+
+	sink, err := NewJournaldSink(JournaldConfig{SocketPath: path})
+	if err != nil {
+		t.Fatalf("NewJournaldSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	// journald 重启：socket 消失期间只有第一次写入返回错误，其余条目被丢弃
+	server.Close()
+	os.Remove(path)
+	var failures int
+	for i := 0; i < 20; i++ {
+		if sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelInfo, Message: "lost"}) != nil {
+			failures++
+		}
+	}
+	if failures != 1 {
+		t.Errorf("got %d write errors, want 1", failures)
+	}
+	if stats := sink.Stats(); stats.Connected || stats.Dropped != 20 {
+		t.Errorf("Stats = %+v, want 20 dropped while disconnected", stats)
+	}
+
+	server, err = net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram failed: %v", err)
+	}
+	defer server.Close()
+
+	time.Sleep(journaldMinBackoff + 50*time.Millisecond)
+	if err := sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelInfo, Message: "back"}); err != nil {
+		t.Fatalf("WriteEntry after backoff: %v", err)
+	}
+	buf := make([]byte, 1<<16)
+	server.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := server.Read(buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if fields := parseJournal(t, buf[:n]); fields["MESSAGE"] != "back" {
+		t.Errorf("MESSAGE = %q, want back", fields["MESSAGE"])
+	}
+}
+
+func TestJournaldSinkWriteAfterClose(t *testing.T) {
+	server, path := listenJournal(t)
+	defer server.Close()
+
+	sink, err := NewJournaldSink(JournaldConfig{SocketPath: path})
+	if err != nil {
+		t.Fatalf("NewJournaldSink failed: %v", err)
+	}
+	sink.Close()
+
+	// 关闭后不应重新连接
+	if err := sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelInfo, Message: "late"}); err != os.ErrClosed {
+		t.Errorf("WriteEntry after Close = %v, want os.ErrClosed", err)
+	}
+	if sink.Stats().Connected {
+		t.Error("Closed sink reconnected")
+	}
+}
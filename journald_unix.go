@@ -0,0 +1,55 @@
+//go:build unix
+
+package logger
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+func journaldSupported() error {
+	return nil
+}
+
+// isMessageTooLarge 判断错误是否由数据报超出大小限制引起
+func isMessageTooLarge(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS)
+}
+
+// sendJournalViaFile 将数据写入已删除的临时文件，并通过 SCM_RIGHTS 传递文件描述符
+func sendJournalViaFile(conn *net.UnixConn, data []byte) error {
+	dir := "/dev/shm"
+	if _, err := os.Stat(dir); err != nil {
+		dir = os.TempDir()
+	}
+
+	file, err := os.CreateTemp(dir, "journal-")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+
+	// 已连接的数据报 socket 不能使用 WriteMsgUnix，直接调用 sendmsg
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	rights := syscall.UnixRights(int(file.Fd()))
+	var sendErr error
+	err = raw.Write(func(fd uintptr) bool {
+		sendErr = syscall.Sendmsg(int(fd), nil, rights, nil, 0)
+		return sendErr != syscall.EAGAIN
+	})
+	if err != nil {
+		return err
+	}
+	return sendErr
+}
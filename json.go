@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// entryJSON 是 LogEntry 的 JSON 表示
+type entryJSON struct {
+	Time   string    `json:"time"`
+	Level  string    `json:"level"`
+	Prefix string    `json:"prefix,omitempty"`
+	Logger string    `json:"logger,omitempty"`
+	Msg    string    `json:"msg"`
+	Fields fieldList `json:"fields,omitempty"`
+	File   string    `json:"file,omitempty"`
+	Line   int       `json:"line,omitempty"`
+	Func   string    `json:"func,omitempty"`
+	Stack  string    `json:"stack,omitempty"`
+}
+
+// fieldList 以保持顺序的 JSON 对象形式编码字段
+type fieldList []Field
+
+// MarshalJSON 按字段顺序输出 JSON 对象
+func (l fieldList) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range l {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.Any())
+		if err != nil {
+			// 无法编码的值退化为文本形式
+			value, _ = json.Marshal(f.String())
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON 按出现顺序解析 JSON 对象
+func (l *fieldList) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("fields must be a JSON object")
+	}
+
+	var fields fieldList
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		fields = append(fields, Field{Key: tok.(string), Value: value})
+	}
+
+	*l = fields
+	return nil
+}
+
+// MarshalJSON encodes the entry as a flat JSON object with an RFC 3339 timestamp
+func (e LogEntry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(entryJSON{
+		Time:   e.Timestamp.Format(time.RFC3339Nano),
+		Level:  e.Level,
+		Prefix: e.Prefix,
+		Logger: e.Logger,
+		Msg:    e.Message,
+		Fields: e.Fields,
+		File:   e.File,
+		Line:   e.Line,
+		Func:   e.Function,
+		Stack:  string(e.StackTrace),
+	})
+}
+
+// UnmarshalJSON decodes an entry produced by MarshalJSON
+func (e *LogEntry) UnmarshalJSON(data []byte) error {
+	var v entryJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	var timestamp time.Time
+	if v.Time != "" {
+		var err error
+		if timestamp, err = time.Parse(time.RFC3339Nano, v.Time); err != nil {
+			return err
+		}
+	}
+
+	*e = LogEntry{
+		Timestamp:  timestamp,
+		Level:      v.Level,
+		Message:    v.Msg,
+		Prefix:     v.Prefix,
+		Logger:     v.Logger,
+		Fields:     v.Fields,
+		File:       v.File,
+		Line:       v.Line,
+		Function:   v.Func,
+		StackTrace: []byte(v.Stack),
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StackPolicy 控制某个级别的堆栈采集方式
+type StackPolicy int
+
+// StackPolicy constants
+const (
+	StackNone  StackPolicy = iota // 不采集堆栈
+	StackEntry                    // 仅记录到 LogEntry.StackTrace
+	StackPrint                    // 记录到 LogEntry 并打印到输出
+)
+
+// LevelSpec describes a log level
+type LevelSpec struct {
+	Name     string      // 级别名称，统一为大写
+	Severity int         // 严重程度，数值越大越严重（与 OpenTelemetry SeverityNumber 对齐）
+	Color    string      // 控制台 ANSI 颜色序列，为空则不着色
+	Stack    StackPolicy // 堆栈策略
+	Always   bool        // 忽略最低级别过滤，总是输出
+}
+
+// ANSI 颜色序列
+const (
+	colorReset   = "\x1b[0m"
+	colorGray    = "\x1b[90m"
+	colorCyan    = "\x1b[36m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorRed     = "\x1b[31m"
+	colorMagenta = "\x1b[35m"
+)
+
+var (
+	// 内置级别，不允许被覆盖
+	builtinLevels = []LevelSpec{
+		{Name: LevelTrace, Severity: 1, Color: colorGray, Stack: StackNone},
+		{Name: LevelDebug, Severity: 5, Color: colorCyan, Stack: StackEntry},
+		{Name: LevelInfo, Severity: 9, Color: colorGreen, Stack: StackNone},
+		{Name: LevelWarn, Severity: 13, Color: colorYellow, Stack: StackEntry},
+		{Name: LevelError, Severity: 17, Color: colorRed, Stack: StackPrint},
+		{Name: LevelFatal, Severity: 21, Color: colorMagenta, Stack: StackPrint},
+	}
+
+	// 内置级别不可修改，日志函数直接使用，无需查表
+	specTrace = builtinLevels[0]
+	specDebug = builtinLevels[1]
+	specInfo  = builtinLevels[2]
+	specWarn  = builtinLevels[3]
+	specError = builtinLevels[4]
+	specFatal = builtinLevels[5]
+)
+
+// RegisterLevel registers a custom log level or updates a previously registered one.
+// A LOG_LEVEL value that named the level before it was registered is applied now.
+func RegisterLevel(spec LevelSpec) error {
+	spec.Name = strings.ToUpper(strings.TrimSpace(spec.Name))
+	if spec.Name == "" {
+		return fmt.Errorf("level name must not be empty")
+	}
+	if isBuiltinLevel(spec.Name) {
+		return fmt.Errorf("level %q is builtin and cannot be redefined", spec.Name)
+	}
+
+	err := updateConfig(func(c *runtimeConfig) error {
+		c.levels = copyLevels(c.levels, 1)
+		c.levels[spec.Name] = spec
+
+		// 最低级别保存的是级别的副本，更新已注册的级别时一并替换
+		if c.minLevel.Name == spec.Name {
+			c.minLevel = spec
+		}
+		copied := false
+		for name, level := range c.loggerLevels {
+			if level.Name != spec.Name {
+				continue
+			}
+			if !copied {
+				c.loggerLevels = copyLevels(c.loggerLevels, 0)
+				copied = true
+			}
+			c.loggerLevels[name] = spec
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	retryEnvLevels()
+	return nil
+}
+
+// GetLevelSpec 根据名称查找级别，名称不区分大小写
+func GetLevelSpec(name string) (LevelSpec, bool) {
+	spec, ok := currentConfig().levels[strings.ToUpper(name)]
+	return spec, ok
+}
+
+// Levels 返回所有已注册的级别，按严重程度升序排列
+func Levels() []LevelSpec {
+	return sortedLevels(currentConfig().levels)
+}
+
+func sortedLevels(levels map[string]LevelSpec) []LevelSpec {
+	specs := make([]LevelSpec, 0, len(levels))
+	for _, spec := range levels {
+		specs = append(specs, spec)
+	}
+
+	sort.Slice(specs, func(i, j int) bool {
+		if specs[i].Severity != specs[j].Severity {
+			return specs[i].Severity < specs[j].Severity
+		}
+		return specs[i].Name < specs[j].Name
+	})
+	return specs
+}
+
+// SetLevel sets the minimum level written to outputs
+func SetLevel(name string) error {
+	return updateConfig(func(c *runtimeConfig) error {
+		spec, ok := c.levels[strings.ToUpper(name)]
+		if !ok {
+			return fmt.Errorf("unknown level %q", name)
+		}
+		c.minLevel = spec
+		return nil
+	})
+}
+
+// GetLevel 返回当前输出的最低级别名称
+func GetLevel() string {
+	return currentConfig().minLevel.Name
+}
+
+// SetLoggerLevel sets the minimum level for the named logger, overriding the
+// global level. An empty level removes the override.
+func SetLoggerLevel(name, level string) error {
+	return updateConfig(func(c *runtimeConfig) error {
+		if level == "" {
+			if _, ok := c.loggerLevels[name]; ok {
+				c.loggerLevels = copyLevels(c.loggerLevels, 0)
+				delete(c.loggerLevels, name)
+			}
+			return nil
+		}
+
+		spec, ok := c.levels[strings.ToUpper(level)]
+		if !ok {
+			return fmt.Errorf("unknown level %q", level)
+		}
+		c.loggerLevels = copyLevels(c.loggerLevels, 1)
+		c.loggerLevels[name] = spec
+		return nil
+	})
+}
+
+// GetLoggerLevel 返回命名 logger 生效的最低级别名称
+func GetLoggerLevel(name string) string {
+	c := currentConfig()
+	if spec, ok := c.loggerLevels[name]; ok {
+		return spec.Name
+	}
+	return c.minLevel.Name
+}
+
+// LoggerLevels 返回所有设置了独立级别的命名 logger
+func LoggerLevels() map[string]string {
+	loggerLevels := currentConfig().loggerLevels
+	result := make(map[string]string, len(loggerLevels))
+	for name, spec := range loggerLevels {
+		result[name] = spec.Name
+	}
+	return result
+}
+
+// resolveLevel 查找级别，未注册的级别按 INFO 的规格处理但保留原名
+func resolveLevel(name string) LevelSpec {
+	if spec, ok := GetLevelSpec(name); ok {
+		return spec
+	}
+	spec := specInfo
+	spec.Name = strings.ToUpper(name)
+	return spec
+}
+
+// outputEnabled 判断该级别在当前配置下是否达到输出的最低级别，name 为命名 logger 名称
+func outputEnabled(spec LevelSpec, name string) bool {
+	return currentConfig().outputEnabled(spec, name)
+}
+
+// stepLevel 将全局最低级别沿严重程度循环移动 delta 步，返回变更前后的级别名称
+// 始终输出的级别不参与循环
+func stepLevel(delta int) (from, to string) {
+	updateConfig(func(c *runtimeConfig) error {
+		var cycle []LevelSpec
+		for _, spec := range sortedLevels(c.levels) {
+			if !spec.Always {
+				cycle = append(cycle, spec)
+			}
+		}
+
+		current := 0
+		for i, spec := range cycle {
+			if spec.Name == c.minLevel.Name {
+				current = i
+				break
+			}
+		}
+
+		next := ((current+delta)%len(cycle) + len(cycle)) % len(cycle)
+		from = c.minLevel.Name
+		c.minLevel = cycle[next]
+		to = c.minLevel.Name
+		return nil
+	})
+	return from, to
+}
+
+// levelAtLeast 判断 name 级别是否不低于 min 级别，min 为空时总是成立
+func levelAtLeast(name, min string) bool {
+	if min == "" {
+		return true
+	}
+	spec := resolveLevel(name)
+	if spec.Always {
+		return true
+	}
+	return spec.Severity >= resolveLevel(min).Severity
+}
+
+func isBuiltinLevel(name string) bool {
+	for _, spec := range builtinLevels {
+		if spec.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// logNotice 以 INFO 级别记录一条忽略级别过滤的内部通知
+func logNotice(format string, args ...any) {
+	spec := specInfo
+	spec.Always = true
+	std.logf(spec, format, args...)
+}
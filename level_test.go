@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTraceBelowDefaultLevel(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	Trace("hidden trace")
+	if strings.Contains(buf.String(), "hidden trace") {
+		t.Error("TRACE should be filtered at default level")
+	}
+
+	if err := SetLevel("trace"); err != nil {
+		t.Fatalf("SetLevel failed: %v", err)
+	}
+	defer SetLevel(LevelDebug)
+
+	Trace("visible trace")
+	if !strings.Contains(buf.String(), "[TRACE] visible trace") {
+		t.Errorf("TRACE message not found: %s", buf.String())
+	}
+}
+
+func TestSetLevelFiltersOutput(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	if err := SetLevel(LevelWarn); err != nil {
+		t.Fatalf("SetLevel failed: %v", err)
+	}
+	defer SetLevel(LevelDebug)
+
+	Info("filtered info")
+	Warn("kept warn")
+
+	content := buf.String()
+	if strings.Contains(content, "filtered info") {
+		t.Error("INFO should be filtered at WARN level")
+	}
+	if !strings.Contains(content, "[WARN] kept warn") {
+		t.Error("WARN message not found")
+	}
+
+	if err := SetLevel("nope"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
+
+func TestRegisterCustomLevel(t *testing.T) {
+	err := RegisterLevel(LevelSpec{Name: "audit", Severity: 10, Stack: StackNone, Always: true})
+	if err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+
+	if err := RegisterLevel(LevelSpec{Name: LevelInfo, Severity: 100}); err == nil {
+		t.Error("Expected error when redefining builtin level")
+	}
+	if err := RegisterLevel(LevelSpec{Name: " "}); err == nil {
+		t.Error("Expected error for empty level name")
+	}
+
+	spec, ok := GetLevelSpec("AUDIT")
+	if !ok || spec.Name != "AUDIT" || !spec.Always {
+		t.Fatalf("Unexpected spec: %+v", spec)
+	}
+
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	SetLevel(LevelFatal)
+	defer SetLevel(LevelDebug)
+
+	ch := GetLogChannelWithConfig("audit-channel", LogChannelConfig{BufferSize: 4, Level: LevelError})
+	defer RemoveLogChannel("audit-channel")
+
+	Log("audit", "user %s logged in", "alice")
+
+	if !strings.Contains(buf.String(), "[AUDIT] user alice logged in") {
+		t.Errorf("AUDIT should always be written: %s", buf.String())
+	}
+
+	select {
+	case entry := <-ch:
+		if entry.Level != "AUDIT" {
+			t.Errorf("Unexpected level %q", entry.Level)
+		}
+	case <-time.After(time.Second):
+		t.Error("AUDIT entry not delivered to channel")
+	}
+}
+
+func TestChannelLevelFilter(t *testing.T) {
+	ch := GetLogChannelWithConfig("errors-only", LogChannelConfig{BufferSize: 4, Level: LevelError})
+	defer RemoveLogChannel("errors-only")
+
+	Info("not for this channel")
+	Error("for this channel")
+
+	select {
+	case entry := <-ch:
+		if entry.Level != LevelError {
+			t.Errorf("Expected ERROR entry, got %s", entry.Level)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for ERROR entry")
+	}
+
+	select {
+	case entry := <-ch:
+		t.Errorf("Unexpected extra entry: %+v", entry)
+	default:
+	}
+}
+
+func TestLevelsSorted(t *testing.T) {
+	specs := Levels()
+	for i := 1; i < len(specs); i++ {
+		if specs[i-1].Severity > specs[i].Severity {
+			t.Fatalf("Levels not sorted: %v", specs)
+		}
+	}
+	if specs[0].Name != LevelTrace {
+		t.Errorf("Expected TRACE first, got %s", specs[0].Name)
+	}
+}
+
+func TestReregisterLevelUpdatesMinimum(t *testing.T) {
+	if err := RegisterLevel(LevelSpec{Name: "rereg", Severity: 8, Always: true}); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+	SetLevel("rereg")
+	defer SetLevel(LevelDebug)
+	SetLoggerLevel("rereg-db", "rereg")
+	defer SetLoggerLevel("rereg-db", "")
+
+	if err := RegisterLevel(LevelSpec{Name: "rereg", Severity: 16, Always: true}); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+
+	c := currentConfig()
+	if c.minLevel.Severity != 16 || c.loggerLevels["rereg-db"].Severity != 16 {
+		t.Errorf("Stale severity: global %d, rereg-db %d", c.minLevel.Severity, c.loggerLevels["rereg-db"].Severity)
+	}
+	if outputEnabled(specWarn, "") || outputEnabled(specWarn, "rereg-db") {
+		t.Error("WARN should be below the updated minimum")
+	}
+}
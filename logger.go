@@ -3,16 +3,17 @@ package logger
 
 import (
 	"fmt"
-	"io"
-	"log"
-	"os"
+	"runtime"
 	"runtime/debug"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // LogLevel constants
 const (
+	LevelTrace = "TRACE"
 	LevelDebug = "DEBUG"
 	LevelInfo  = "INFO"
 	LevelWarn  = "WARN"
@@ -21,17 +22,15 @@ const (
 )
 
 var (
-	logger        *log.Logger
-	multiWriter   io.Writer
-	consoleWriter io.Writer
-	customWriter  io.Writer
-	writerMutex   sync.RWMutex
-	activeReader  *io.PipeWriter
+	// outputMutex 串行化输出写入，保证行不交错
+	outputMutex sync.Mutex
 
 	// Channel 相关变量
-	logChannels   map[string]chan LogEntry
+	// logChannels 是最新发布的注册表快照，发布后不再修改；广播通过 channelSet 无锁读取，
+	// 注册和移除在 channelsMutex 下复制并替换快照
+	logChannels   map[string]*logChannel
+	channelSet    atomic.Pointer[channelSnapshot]
 	channelsMutex sync.RWMutex
-	bufferSize    = 100 // 默认缓冲区大小
 )
 
 // LogEntry represents a log message entry
@@ -40,6 +39,11 @@ type LogEntry struct {
 	Level      string
 	Message    string
 	Prefix     string
+	Logger     string  // 命名 logger 名称，包级函数为空
+	Fields     []Field // 结构化字段
+	File       string  // 调用方源文件
+	Line       int     // 调用方行号
+	Function   string  // 调用方函数全名
 	StackTrace []byte
 }
 
@@ -47,86 +51,62 @@ type LogEntry struct {
 type LogChannelConfig struct {
 	BufferSize int           // 缓冲区大小
 	Timeout    time.Duration // 发送超时时间
+	Level      string        // 最低接收级别，为空则接收所有级别
 }
 
-var productName string
-
-func init() {
-	consoleWriter = os.Stdout
-	multiWriter = consoleWriter
-	logger = log.New(multiWriter, "", log.Ldate|log.Ltime|log.Lmsgprefix)
-
-	// 初始化 channel 映射
-	logChannels = make(map[string]chan LogEntry)
-
-	// TODO: add log rotation
+// ChannelStats 描述一个命名日志 channel 的状态
+type ChannelStats struct {
+	Name       string `json:"name"`
+	Level      string `json:"level,omitempty"`
+	BufferSize int    `json:"buffer_size"`
+	Buffered   int    `json:"buffered"` // 当前缓冲区中未读取的条目数
+	Sent       uint64 `json:"sent"`     // 成功放入缓冲区的条目数
+	Dropped    uint64 `json:"dropped"`  // 因缓冲区满而丢弃的条目数
 }
 
-// SetProductName updates the prefix
-func SetProductName(name string) {
-	productName = name
-	logger.SetPrefix(fmt.Sprintf("[%v] ", name))
+// logChannel 是一个已注册的日志 channel 及其统计信息
+type logChannel struct {
+	name     string
+	ch       chan LogEntry
+	settings atomic.Pointer[channelSettings] // 可在运行时替换，缓冲区大小除外
+	sent     atomic.Uint64
+	dropped  atomic.Uint64
+
+	// mu 保护发送与关闭：广播方可能仍持有移除前的快照，
+	// 发送时持读锁并检查 closed，避免向已关闭的 channel 发送
+	mu     sync.RWMutex
+	closed bool
 }
 
-// SetOutput sets the output destination for the logger
-// This replaces the default console output
-func SetOutput(w io.Writer) {
-	writerMutex.Lock()
-	defer writerMutex.Unlock()
-
-	customWriter = w
-	updateMultiWriter()
+// channelSettings 是 channel 的不可变配置，minLevel 在设置时由 config.Level 解析
+type channelSettings struct {
+	config   LogChannelConfig
+	minLevel LevelSpec
 }
 
-// GetReaderCopy returns a copy of the logger output that can be read from
-// This allows reading log output while still writing to console
-func GetReaderCopy() (io.Reader, error) {
-	writerMutex.Lock()
-	defer writerMutex.Unlock()
-
-	if customWriter == nil {
-		return nil, fmt.Errorf("no custom writer set, call SetOutput first")
-	}
-
-	// 如果已经有活跃的 reader，先关闭它
-	if activeReader != nil {
-		activeReader.Close()
+func newChannelSettings(config LogChannelConfig) *channelSettings {
+	settings := &channelSettings{config: config}
+	if config.Level != "" {
+		settings.minLevel = resolveLevel(config.Level)
 	}
-
-	// 创建新的管道
-	reader, writer := io.Pipe()
-	activeReader = writer
-
-	// 更新多写入器包含管道写入器
-	updateMultiWriter()
-
-	return reader, nil
+	return settings
 }
 
-// RemoveReaderCopy removes the reader copy from logger output
-func RemoveReaderCopy() {
-	writerMutex.Lock()
-	defer writerMutex.Unlock()
-
-	if activeReader != nil {
-		activeReader.Close()
-		activeReader = nil
-		updateMultiWriter()
-	}
+// channelSnapshot 是不可变的 channel 注册表
+type channelSnapshot struct {
+	byName map[string]*logChannel
+	list   []*logChannel // 按名称排序，广播时遍历
 }
 
-// SetChannelBufferSize 设置 channel 缓冲区大小
-func SetChannelBufferSize(size int) {
-	if size <= 0 {
-		size = 100 // 默认值
-	}
-	bufferSize = size
+func init() {
+	// 初始化 channel 注册表
+	publishChannels(make(map[string]*logChannel))
 }
 
 // GetLogChannel 创建或获取指定名称的日志 channel
 func GetLogChannel(name string) <-chan LogEntry {
 	return GetLogChannelWithConfig(name, LogChannelConfig{
-		BufferSize: bufferSize,
+		BufferSize: currentConfig().bufferSize,
 		Timeout:    100 * time.Millisecond,
 	})
 }
@@ -137,15 +117,48 @@ func GetLogChannelWithConfig(name string, config LogChannelConfig) <-chan LogEnt
 	defer channelsMutex.Unlock()
 
 	// 如果 channel 已存在，返回它
-	if ch, exists := logChannels[name]; exists {
-		return ch
+	if lc, exists := logChannels[name]; exists {
+		return lc.ch
 	}
 
 	// 创建新的 channel
-	ch := make(chan LogEntry, config.BufferSize)
-	logChannels[name] = ch
+	lc := &logChannel{
+		name: name,
+		ch:   make(chan LogEntry, config.BufferSize),
+	}
+	lc.settings.Store(newChannelSettings(config))
 
-	return ch
+	next := make(map[string]*logChannel, len(logChannels)+1)
+	for n, c := range logChannels {
+		next[n] = c
+	}
+	next[name] = lc
+	publishChannels(next)
+
+	return lc.ch
+}
+
+// logChannelConfig 返回已注册 channel 的配置，BufferSize 为实际容量
+func logChannelConfig(name string) (LogChannelConfig, bool) {
+	lc, exists := channelSet.Load().byName[name]
+	if !exists {
+		return LogChannelConfig{}, false
+	}
+	config := lc.settings.Load().config
+	config.BufferSize = cap(lc.ch)
+	return config, true
+}
+
+// reconfigureLogChannel 替换已注册 channel 的级别和超时。
+// 订阅方持有的 channel 不变，因此缓冲区大小无法修改，config.BufferSize 被忽略
+func reconfigureLogChannel(name string, config LogChannelConfig) bool {
+	lc, exists := channelSet.Load().byName[name]
+	if !exists {
+		return false
+	}
+	config.BufferSize = cap(lc.ch)
+	lc.settings.Store(newChannelSettings(config))
+	return true
 }
 
 // RemoveLogChannel 移除指定名称的日志 channel
@@ -153,152 +166,391 @@ func RemoveLogChannel(name string) {
 	channelsMutex.Lock()
 	defer channelsMutex.Unlock()
 
-	if ch, exists := logChannels[name]; exists {
-		close(ch)
-		delete(logChannels, name)
+	lc, exists := logChannels[name]
+	if !exists {
+		return
+	}
+
+	next := make(map[string]*logChannel, len(logChannels))
+	for n, c := range logChannels {
+		if n != name {
+			next[n] = c
+		}
+	}
+	publishChannels(next)
+
+	// 先发布新快照再关闭，等待持有旧快照的广播完成当前发送
+	lc.mu.Lock()
+	lc.closed = true
+	close(lc.ch)
+	lc.mu.Unlock()
+}
+
+// publishChannels 发布新的注册表快照，调用方持有 channelsMutex 或处于 init
+func publishChannels(byName map[string]*logChannel) {
+	list := make([]*logChannel, 0, len(byName))
+	for _, lc := range byName {
+		list = append(list, lc)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].name < list[j].name
+	})
+
+	logChannels = byName
+	channelSet.Store(&channelSnapshot{byName: byName, list: list})
+}
+
+// hasChannels 判断是否有已注册的 channel
+func hasChannels() bool {
+	return len(channelSet.Load().list) > 0
+}
+
+// GetChannelStats 返回所有日志 channel 的统计信息，按名称排序
+func GetChannelStats() []ChannelStats {
+	snapshot := channelSet.Load()
+	stats := make([]ChannelStats, 0, len(snapshot.list))
+	for _, lc := range snapshot.list {
+		stats = append(stats, ChannelStats{
+			Name:       lc.name,
+			Level:      lc.settings.Load().config.Level,
+			BufferSize: cap(lc.ch),
+			Buffered:   len(lc.ch),
+			Sent:       lc.sent.Load(),
+			Dropped:    lc.dropped.Load(),
+		})
 	}
+	return stats
 }
 
 // broadcastToChannels 广播日志条目到所有 channel
-// 实现丢弃最旧日志的机制
+// 实现丢弃最旧日志的机制。注册表快照无锁读取，只有同一 channel 的发送与关闭互斥
 func broadcastToChannels(entry LogEntry) {
-	channelsMutex.RLock()
-	defer channelsMutex.RUnlock()
+	snapshot := channelSet.Load()
+	if len(snapshot.list) == 0 {
+		return
+	}
+
+	spec := resolveLevel(entry.Level)
+	for _, lc := range snapshot.list {
+		settings := lc.settings.Load()
+		if settings.config.Level != "" && !spec.Always && spec.Severity < settings.minLevel.Severity {
+			continue
+		}
 
-	for name, ch := range logChannels {
+		lc.mu.RLock()
+		if !lc.closed {
+			lc.send(entry)
+		}
+		lc.mu.RUnlock()
+	}
+}
+
+// send 发送条目，缓冲区满时丢弃最旧的条目，调用方持有 lc.mu 读锁
+func (lc *logChannel) send(entry LogEntry) {
+	ch := lc.ch
+	select {
+	case ch <- entry:
+		// 成功发送
+		lc.sent.Add(1)
+	default:
+		// 缓冲区满，丢弃最旧的日志条目
 		select {
-		case ch <- entry:
-			// 成功发送
-		default:
-			// 缓冲区满，丢弃最旧的日志条目
+		case <-ch:
+			// 成功丢弃最旧条目，现在可以发送新条目
+			lc.dropped.Add(1)
 			select {
-			case <-ch:
-				// 成功丢弃最旧条目，现在可以发送新条目
-				select {
-				case ch <- entry:
-					// 成功发送
-				default:
-					// 极少数情况下仍然无法发送，记录警告
-					fmt.Fprintf(os.Stderr, "WARNING: Log channel '%s' still full after dropping oldest entry\n", name)
-				}
+			case ch <- entry:
+				// 成功发送
+				lc.sent.Add(1)
 			default:
-				// 无法丢弃最旧条目（可能 channel 已关闭），记录警告
-				fmt.Fprintf(os.Stderr, "WARNING: Cannot drop oldest entry from log channel '%s'\n", name)
+				// 极少数情况下仍然无法发送，记录警告
+				lc.dropped.Add(1)
+				warnf("Log channel '%s' still full after dropping oldest entry", lc.name)
 			}
+		default:
+			// 无法丢弃最旧条目（可能 channel 已关闭），记录警告
+			lc.dropped.Add(1)
+			warnf("Cannot drop oldest entry from log channel '%s'", lc.name)
 		}
 	}
 }
 
-// updateMultiWriter 更新多写入器配置
-func updateMultiWriter() {
-	writers := []io.Writer{consoleWriter}
+// Trace prints log message with TRACE level
+func Trace(format string, args ...any) {
+	std.logf(specTrace, format, args...)
+}
 
-	if customWriter != nil {
-		writers = append(writers, customWriter)
+// Debug prints log message with DEBUG level
+func Debug(format string, args ...any) {
+	std.logf(specDebug, format, args...)
+}
+
+// Info prints log message with INFO level
+func Info(format string, args ...any) {
+	std.logf(specInfo, format, args...)
+}
+
+// Warn prints log message with WARN level
+func Warn(format string, args ...any) {
+	std.logf(specWarn, format, args...)
+}
+
+// Error prints log message with ERROR level
+func Error(format string, args ...any) {
+	std.logf(specError, format, args...)
+}
+
+// Fatal prints log message with FATAL level, closes the registered sinks so
+// that queued entries are sent, and calls os.Exit(1)
+func Fatal(format string, args ...any) {
+	std.logf(specFatal, format, args...)
+	exit(1)
+}
+
+// Tracew logs msg with TRACE level and the given fields. Unlike Trace, msg
+// is not a format string; with typed fields it does not allocate when the
+// level is disabled.
+func Tracew(msg string, fields ...Field) {
+	std.logw(specTrace, msg, fields...)
+}
+
+// Debugw logs msg with DEBUG level and the given fields
+func Debugw(msg string, fields ...Field) {
+	std.logw(specDebug, msg, fields...)
+}
+
+// Infow logs msg with INFO level and the given fields
+func Infow(msg string, fields ...Field) {
+	std.logw(specInfo, msg, fields...)
+}
+
+// Warnw logs msg with WARN level and the given fields
+func Warnw(msg string, fields ...Field) {
+	std.logw(specWarn, msg, fields...)
+}
+
+// Errorw logs msg with ERROR level and the given fields
+func Errorw(msg string, fields ...Field) {
+	std.logw(specError, msg, fields...)
+}
+
+// Log prints log message with the given level, which may be a custom level
+// registered via RegisterLevel
+func Log(level string, format string, args ...any) {
+	std.logf(resolveLevel(level), format, args...)
+}
+
+// logf 按格式字符串记录一条日志
+func (l *Logger) logf(spec LevelSpec, format string, args ...any) {
+	l.log(callerSkip, spec, format, true, args, nil)
+}
+
+// logw 记录一条不经格式化的消息并附加字段
+func (l *Logger) logw(spec LevelSpec, msg string, fields ...Field) {
+	l.log(callerSkip, spec, msg, false, nil, fields)
+}
+
+// log 是所有日志函数的公共路径。级别未启用且没有 channel 订阅时不做任何工作；
+// 没有 channel、sink、折叠和脱敏时消息直接格式化到池化缓冲区并写出，不构造 LogEntry。
+// fields 只被读取或复制，不会被保存，调用方的可变参数切片因此可以分配在栈上。
+// skip 是传给 setCaller 的栈帧数，writerCaller 表示经由 Writer 写入
+func (l *Logger) log(skip int, spec LevelSpec, template string, format bool, args []any, fields []Field) {
+	c := currentConfig()
+	if !c.outputEnabled(spec, l.name) && !hasChannels() && len(l.sinks) == 0 && observerCount.Load() == 0 {
+		return
 	}
+	// 采样在格式化和采集堆栈之前进行，被抑制的消息几乎没有开销
+	if !sampleAllowed(spec, l.name, template) {
+		return
+	}
+	args = resolveArgs(args)
 
-	if activeReader != nil {
-		writers = append(writers, activeReader)
+	if !needEntry() && len(l.sinks) == 0 {
+		l.writeDirect(c, spec, template, format, args, fields)
+		return
 	}
 
-	if len(writers) == 1 {
-		multiWriter = writers[0]
+	var message string
+	if format {
+		message = fmt.Sprintf(template, args...)
 	} else {
-		multiWriter = io.MultiWriter(writers...)
+		message = template
+	}
+	entry := l.newEntry(c, spec, message)
+	if len(fields) > 0 {
+		entry.Fields = mergeFields(entry.Fields, fields)
 	}
+	entry.setCaller(skip)
+	emit(spec, template, entry, l.sinks)
+}
 
-	logger.SetOutput(multiWriter)
+// needEntry 判断是否有需要完整 LogEntry 的消费者
+func needEntry() bool {
+	return hasChannels() || sinkCount.Load() > 0 ||
+		activeCollapser.Load() != nil || activeRedactor.Load() != nil
 }
 
-// Debug prints log message with DEBUG level
-func Debug(format string, args ...any) {
-	message := fmt.Sprintf(format, args...)
-	entry := LogEntry{
-		Timestamp:  time.Now(),
-		Level:      LevelDebug,
-		Message:    message,
-		Prefix:     GetPrefix(),
-		StackTrace: debug.Stack(),
-	}
+// writeDirect 将消息直接格式化到池化缓冲区并写出，仅在没有条目消费者时使用
+func (l *Logger) writeDirect(c *runtimeConfig, spec LevelSpec, template string, format bool, args []any, fields []Field) {
+	bp := getBuffer()
+	defer putBuffer(bp)
 
-	// 广播到所有 channel
-	broadcastToChannels(entry)
+	buf, tag := appendLineStart((*bp)[:0], c, time.Now(), spec, l.name)
+	start := len(buf)
+	if format {
+		buf = fmt.Appendf(buf, template, args...)
+	} else {
+		buf = append(buf, template...)
+	}
+	if len(buf) > start && buf[len(buf)-1] == '\n' {
+		buf = buf[:len(buf)-1]
+	}
+	buf = appendFields(buf, l.fields)
+	buf = appendFields(buf, fields)
+	buf = append(buf, '\n')
+	if spec.Stack == StackPrint {
+		buf = appendStack(buf)
+	}
 
-	logger.Printf("[DEBUG] " + stripNewline(message) + "\n")
+	*bp = buf
+	writeLine(c, spec, buf, tag)
 }
 
-// Info prints log message with INFO level
-func Info(format string, args ...any) {
-	message := fmt.Sprintf(format, args...)
+// newEntry 创建日志条目，按级别的堆栈策略采集堆栈
+func (l *Logger) newEntry(c *runtimeConfig, spec LevelSpec, message string) LogEntry {
 	entry := LogEntry{
 		Timestamp:  time.Now(),
-		Level:      LevelInfo,
+		Level:      spec.Name,
 		Message:    message,
-		Prefix:     GetPrefix(),
-		StackTrace: []byte{}, // Info 级别不包含堆栈信息
+		Prefix:     c.productName,
+		Logger:     l.name,
+		Fields:     resolveFields(l.fields),
+		StackTrace: []byte{},
+	}
+	if spec.Stack != StackNone {
+		entry.StackTrace = debug.Stack()
 	}
+	return entry
+}
 
-	// 广播到所有 channel
-	broadcastToChannels(entry)
+// emit 将条目脱敏后写入 logger 自己的 sink（没有时写入 ObserverSink）、广播到 channel，
+// 并在级别允许时写入 sink 和日志输出。启用重复折叠时由折叠器决定条目何时送达全局输出
+func emit(spec LevelSpec, format string, entry LogEntry, scoped []Sink) {
+	entry = redactEntry(entry)
+	for _, s := range scoped {
+		if err := s.WriteEntry(entry); err != nil {
+			warnf("Log sink write failed: %v", err)
+		}
+	}
+	if len(scoped) == 0 {
+		writeObservers(entry)
+	}
 
-	logger.Printf("[INFO] " + stripNewline(message) + "\n")
+	c := activeCollapser.Load()
+	if c == nil {
+		broadcastToChannels(entry)
+		writeOutputs(spec, entry)
+		return
+	}
+	c.submit(spec, format, entry)
 }
 
-// Warn prints log message with WARN level
-func Warn(format string, args ...any) {
-	message := fmt.Sprintf(format, args...)
-	entry := LogEntry{
-		Timestamp:  time.Now(),
-		Level:      LevelWarn,
-		Message:    message,
-		Prefix:     GetPrefix(),
-		StackTrace: debug.Stack(),
+// writeOutputs 在级别允许时将条目写入 sink 和日志输出
+func writeOutputs(spec LevelSpec, entry LogEntry) {
+	c := currentConfig()
+	if !c.outputEnabled(spec, entry.Logger) {
+		return
 	}
 
-	// 广播到所有 channel
-	broadcastToChannels(entry)
+	writeSinks(entry)
 
-	logger.Printf("[WARN] " + stripNewline(message) + "\n")
-}
+	bp := getBuffer()
+	defer putBuffer(bp)
 
-// Error prints log message with ERROR level
-func Error(format string, args ...any) {
-	message := fmt.Sprintf(format, args...)
-	entry := LogEntry{
-		Timestamp:  time.Now(),
-		Level:      LevelError,
-		Message:    message,
-		Prefix:     GetPrefix(),
-		StackTrace: debug.Stack(),
+	buf, tag := appendLineStart((*bp)[:0], c, entry.Timestamp, spec, entry.Logger)
+	buf = append(buf, stripNewline(entry.Message)...)
+	buf = appendFields(buf, entry.Fields)
+	buf = append(buf, '\n')
+	if spec.Stack == StackPrint {
+		buf = append(buf, entry.StackTrace...)
 	}
 
-	// 广播到所有 channel
-	broadcastToChannels(entry)
+	*bp = buf
+	writeLine(c, spec, buf, tag)
+}
 
-	logger.Printf("[ERROR] " + stripNewline(message) + "\n" + string(debug.Stack()))
+// appendLineStart 追加 "时间 [前缀] [LEVEL] [logger] "，与 log.Ldate|log.Ltime|log.Lmsgprefix 格式一致。
+// 返回级别标签在缓冲区中的起始位置，供彩色输出替换
+func appendLineStart(buf []byte, c *runtimeConfig, now time.Time, spec LevelSpec, name string) ([]byte, int) {
+	buf = now.AppendFormat(buf, TextTimeLayout)
+	buf = append(buf, ' ')
+	buf = append(buf, c.linePrefix...)
+
+	tag := len(buf)
+	buf = append(buf, '[')
+	buf = append(buf, spec.Name...)
+	buf = append(buf, "] "...)
+	if name != "" {
+		buf = append(buf, '[')
+		buf = append(buf, name...)
+		buf = append(buf, "] "...)
+	}
+	return buf, tag
 }
 
-// Fatal prints log message with FATAL level and calls os.Exit(1)
-func Fatal(format string, args ...any) {
-	message := fmt.Sprintf(format, args...)
-	entry := LogEntry{
-		Timestamp:  time.Now(),
-		Level:      LevelFatal,
-		Message:    message,
-		Prefix:     GetPrefix(),
-		StackTrace: debug.Stack(),
+// writeLine 将一行写入输出；启用颜色时另写一份带颜色级别标签的副本到控制台
+func writeLine(c *runtimeConfig, spec LevelSpec, line []byte, tag int) {
+	if line[len(line)-1] != '\n' {
+		line = append(line, '\n')
+	}
+
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+
+	c.output.Write(line)
+	if !c.color || c.console == nil {
+		return
 	}
 
-	// 广播到所有 channel
-	broadcastToChannels(entry)
+	bp := getBuffer()
+	defer putBuffer(bp)
 
-	logger.Printf("[FATAL] " + stripNewline(message) + "\n" + string(debug.Stack()))
-	os.Exit(1)
+	end := tag + len(spec.Name) + 2 // "[LEVEL]"
+	buf := append((*bp)[:0], line[:tag]...)
+	buf = append(buf, spec.Color...)
+	buf = append(buf, line[tag:end]...)
+	if spec.Color != "" {
+		buf = append(buf, colorReset...)
+	}
+	buf = append(buf, line[end:]...)
+	*bp = buf
+	c.console.Write(buf)
 }
 
-// GetPrefix 获取当前的日志前缀
-func GetPrefix() string {
-	return productName
+// callerSkip 是从 setCaller 到用户调用处的栈帧数：
+// setCaller -> log -> logf/logw -> Info 等日志函数 -> 调用方
+const callerSkip = 4
+
+// writerCaller 使 setCaller 跳过 Writer 和标准库 log、fmt、io、bufio 的栈帧，
+// 这些栈帧的层数取决于写入方式
+const writerCaller = -1
+
+// setCaller 记录调用日志函数的源码位置
+func (e *LogEntry) setCaller(skip int) {
+	if skip == writerCaller {
+		e.setWriterCaller()
+		return
+	}
+
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return
+	}
+
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	e.File = frame.File
+	e.Line = frame.Line
+	e.Function = frame.Function
 }
 
 func stripNewline(s string) string {
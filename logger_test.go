@@ -298,4 +298,58 @@ func TestRemoveLogChannel(t *testing.T) {
 	if exists {
 		t.Error("Channel should be removed")
 	}
-}
\ No newline at end of file
+}
+
+func TestChannelRegistrationDuringBroadcast(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					Trace("broadcast while registering")
+				}
+			}
+		}()
+	}
+
+	// 持有旧快照的广播不能向已关闭的 channel 发送
+	for i := 0; i < 200; i++ {
+		ch := GetLogChannelWithConfig("churn", LogChannelConfig{BufferSize: 1})
+		RemoveLogChannel("churn")
+		for range ch {
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	for _, s := range GetChannelStats() {
+		if s.Name == "churn" {
+			t.Error("Removed channel still listed")
+		}
+	}
+}
+
+func TestChannelLevelResolvedAtRegistration(t *testing.T) {
+	ch := GetLogChannelWithConfig("warn-only", LogChannelConfig{BufferSize: 10, Level: "warn"})
+	defer RemoveLogChannel("warn-only")
+
+	Info("skipped")
+	Error("kept")
+
+	if len(ch) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(ch))
+	}
+	if e := <-ch; e.Message != "kept" {
+		t.Errorf("Unexpected entry %q", e.Message)
+	}
+}
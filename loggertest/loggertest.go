@@ -0,0 +1,136 @@
+// Package loggertest provides helpers for asserting on log output in tests
+package loggertest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"repo.smlk.org/logger"
+)
+
+// sinkSeq 为注册的 sink 生成唯一名称
+var sinkSeq atomic.Uint64
+
+// Recorder captures the structured entries logged while its test runs, at
+// every level and after redaction. It is registered as an observer sink until
+// the test ends, so it sees the package level functions and named loggers
+// used by the code under test. Those are shared by all running tests; tests
+// using t.Parallel that must not see each other's entries log through Logger,
+// whose entries reach only this recorder.
+type Recorder struct {
+	t      testing.TB
+	name   string
+	logger *logger.Logger
+
+	mu      sync.Mutex
+	entries []logger.LogEntry
+}
+
+// NewRecorder creates a recorder for the test t and removes it when the test ends
+func NewRecorder(t testing.TB) *Recorder {
+	r := &Recorder{t: t, name: fmt.Sprintf("loggertest-%d", sinkSeq.Add(1))}
+	r.logger = logger.WithSink(r)
+	logger.AddSink(r.name, r)
+	t.Cleanup(func() {
+		logger.RemoveSink(r.name)
+	})
+	return r
+}
+
+// WriteEntry 实现 logger.Sink
+func (r *Recorder) WriteEntry(entry logger.LogEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+// Close 实现 logger.Sink
+func (r *Recorder) Close() error {
+	return nil
+}
+
+// Observer 实现 logger.ObserverSink
+func (r *Recorder) Observer() {}
+
+// Logger returns a logger whose entries only r records among the recorders
+// and ToT sinks. Its entries also go to the regular outputs when their level
+// is enabled.
+func (r *Recorder) Logger() *logger.Logger {
+	return r.logger
+}
+
+// Entries 返回已记录条目的副本
+func (r *Recorder) Entries() []logger.LogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]logger.LogEntry(nil), r.entries...)
+}
+
+// Reset 清空已记录的条目
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// Find returns the entries at the given level whose message contains substr.
+// An empty level matches every level; levels compare case-insensitively.
+func (r *Recorder) Find(level, substr string) []logger.LogEntry {
+	var found []logger.LogEntry
+	for _, entry := range r.Entries() {
+		if matches(entry, level, substr) {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// AssertLogged fails the test unless an entry at level contains substr
+func (r *Recorder) AssertLogged(level, substr string) {
+	r.t.Helper()
+
+	if len(r.Find(level, substr)) == 0 {
+		r.t.Errorf("expected %s entry containing %q, recorded:\n%s", levelLabel(level), substr, r.dump())
+	}
+}
+
+// AssertNotLogged fails the test if an entry at level contains substr
+func (r *Recorder) AssertNotLogged(level, substr string) {
+	r.t.Helper()
+
+	if found := r.Find(level, substr); len(found) > 0 {
+		r.t.Errorf("unexpected %s entry containing %q: [%s] %s", levelLabel(level), substr, found[0].Level, found[0].Message)
+	}
+}
+
+// dump 将已记录的条目格式化为每行一条
+func (r *Recorder) dump() string {
+	entries := r.Entries()
+	if len(entries) == 0 {
+		return "\t(none)"
+	}
+
+	var b strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "\t[%s] %s\n", entry.Level, entry.Message)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func matches(entry logger.LogEntry, level, substr string) bool {
+	if level != "" && !strings.EqualFold(entry.Level, level) {
+		return false
+	}
+	return strings.Contains(entry.Message, substr)
+}
+
+func levelLabel(level string) string {
+	if level == "" {
+		return "any"
+	}
+	return strings.ToUpper(level)
+}
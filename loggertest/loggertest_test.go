@@ -0,0 +1,135 @@
+package loggertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"repo.smlk.org/logger"
+)
+
+// fakeT 记录断言失败而不使外层测试失败
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorderCapturesEntries(t *testing.T) {
+	rec := NewRecorder(t)
+	log := rec.Logger()
+
+	log.Info("user %s created", "alice")
+	log.Warn("quota at %d%%", 90)
+
+	rec.AssertLogged(logger.LevelInfo, "alice")
+	rec.AssertLogged("warn", "quota")
+	rec.AssertLogged("", "created")
+	rec.AssertNotLogged(logger.LevelError, "alice")
+
+	if n := len(rec.Entries()); n != 2 {
+		t.Errorf("Expected 2 entries, got %d", n)
+	}
+
+	rec.Reset()
+	if n := len(rec.Entries()); n != 0 {
+		t.Errorf("Reset should clear entries, got %d", n)
+	}
+}
+
+func TestRecorderAssertionFailures(t *testing.T) {
+	rec := NewRecorder(t)
+	ft := &fakeT{TB: t}
+	rec.t = ft
+
+	rec.Logger().Info("present")
+	rec.AssertLogged(logger.LevelError, "present")
+	rec.AssertNotLogged(logger.LevelInfo, "present")
+
+	if len(ft.errors) != 2 {
+		t.Fatalf("Expected 2 assertion failures, got %v", ft.errors)
+	}
+	if !strings.Contains(ft.errors[0], "[INFO] present") {
+		t.Errorf("Failure should list recorded entries: %s", ft.errors[0])
+	}
+}
+
+func TestRecorderCapturesGlobalLogging(t *testing.T) {
+	var rec, other *Recorder
+	t.Run("inner", func(t *testing.T) {
+		rec = NewRecorder(t)
+		other = NewRecorder(t)
+
+		logger.Info("package level")
+		logger.Named("db").With(logger.F("id", 7)).Warn("named")
+		other.Logger().Info("other recorder")
+
+		rec.AssertLogged(logger.LevelInfo, "package level")
+		rec.AssertLogged(logger.LevelWarn, "named")
+		rec.AssertNotLogged("", "other recorder")
+		other.AssertLogged(logger.LevelInfo, "other recorder")
+
+		// 不附加任何标识字段
+		if entry := rec.Find(logger.LevelWarn, "named")[0]; len(entry.Fields) != 1 || entry.Fields[0].Key != "id" {
+			t.Errorf("Unexpected fields: %+v", entry.Fields)
+		}
+	})
+
+	// 测试结束后移除 sink
+	for _, name := range logger.SinkNames() {
+		if name == rec.name || name == other.name {
+			t.Errorf("Sink %s still registered after the test", name)
+		}
+	}
+	logger.Info("after the test")
+	rec.AssertNotLogged("", "after the test")
+}
+
+func TestRecorderRecordsAllLevels(t *testing.T) {
+	rec := NewRecorder(t)
+	old := logger.GetLevel()
+	logger.SetLevel(logger.LevelError)
+	defer logger.SetLevel(old)
+
+	rec.Logger().Trace("below the output level")
+	logger.Named("db").Debug("named below the output level")
+	rec.AssertLogged(logger.LevelTrace, "below the output level")
+	rec.AssertLogged(logger.LevelDebug, "named below the output level")
+}
+
+func TestRecorderParallel(t *testing.T) {
+	for i := 0; i < 4; i++ {
+		i := i
+		t.Run(fmt.Sprintf("worker-%d", i), func(t *testing.T) {
+			t.Parallel()
+			rec := NewRecorder(t)
+			log := rec.Logger()
+
+			logger.Info("package level from worker %d", i)
+			for j := 0; j < 50; j++ {
+				log.Debug("worker %d step %d", i, j)
+			}
+
+			// 包级条目会被所有并行测试看到，Logger 的条目只属于本测试
+			steps := 0
+			for _, entry := range rec.Entries() {
+				if !strings.Contains(entry.Message, " step ") {
+					continue
+				}
+				if !strings.HasPrefix(entry.Message, fmt.Sprintf("worker %d ", i)) {
+					t.Fatalf("Recorded another test's entry: %q", entry.Message)
+				}
+				steps++
+			}
+			if steps != 50 {
+				t.Fatalf("Expected exactly 50 own entries, got %d", steps)
+			}
+			rec.AssertLogged(logger.LevelInfo, fmt.Sprintf("package level from worker %d", i))
+		})
+	}
+}
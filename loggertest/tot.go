@@ -0,0 +1,87 @@
+package loggertest
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"repo.smlk.org/logger"
+)
+
+// TestSink forwards entries to testing.T's log, so they are shown next to
+// the test that produced them (always with -v, otherwise only on failure)
+type TestSink struct {
+	t      testing.TB
+	name   string
+	logger *logger.Logger
+
+	mu   sync.Mutex
+	done bool
+}
+
+// ToT registers an observer sink that logs entries through t.Log until the
+// test ends. The location that testing prints for each line is inside this
+// package; the code that logged the entry is appended as "(at file:line)".
+//
+// Like Recorder, the sink sees the package level functions and named loggers
+// of every running test. Tests using t.Parallel log through Logger to keep
+// their entries apart.
+func ToT(t testing.TB) *TestSink {
+	t.Helper()
+
+	s := &TestSink{t: t, name: fmt.Sprintf("loggertest-t-%d", sinkSeq.Add(1))}
+	s.logger = logger.WithSink(s)
+	logger.AddSink(s.name, s)
+	t.Cleanup(func() {
+		logger.RemoveSink(s.name)
+	})
+	return s
+}
+
+// Logger returns a logger whose entries only s forwards among the recorders
+// and ToT sinks
+func (s *TestSink) Logger() *logger.Logger {
+	return s.logger
+}
+
+// WriteEntry 实现 logger.Sink，测试结束后的条目被丢弃，
+// 避免 "Log in goroutine after Test has completed" panic
+func (s *TestSink) WriteEntry(entry logger.LogEntry) error {
+	line, err := logger.TextFormatter{}.Format(entry)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimPrefix(string(line), entry.Timestamp.Format(logger.TextTimeLayout)+" ")
+	text = strings.TrimSuffix(text, "\n")
+	if entry.File != "" {
+		// t.Log 报告的位置是 sink 内部，Helper 无法跨越日志调用链标记，
+		// 因此将实际调用处作为普通文本附在行尾
+		first, stack, hasStack := strings.Cut(text, "\n")
+		text = fmt.Sprintf("%s (at %s:%d)", first, filepath.Base(entry.File), entry.Line)
+		if hasStack {
+			text += "\n" + stack
+		}
+	}
+
+	// 持锁调用 Log，保证 Close 返回后不再写入
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return nil
+	}
+	s.t.Log(text)
+	return nil
+}
+
+// Close 实现 logger.Sink，在测试结束时由 t.Cleanup 调用
+func (s *TestSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done = true
+	return nil
+}
+
+// Observer 实现 logger.ObserverSink
+func (s *TestSink) Observer() {}
@@ -0,0 +1,92 @@
+package loggertest
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"repo.smlk.org/logger"
+)
+
+// logT 记录 Logf 输出
+type logT struct {
+	testing.TB
+	mu      sync.Mutex
+	lines   []string
+	helpers int
+}
+
+func (l *logT) Helper() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.helpers++
+}
+
+func (l *logT) Log(args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(args...))
+}
+
+func (l *logT) Logf(format string, args ...any) {
+	l.Log(fmt.Sprintf(format, args...))
+}
+
+func (l *logT) logged() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.lines...)
+}
+
+func TestToTForwardsEntries(t *testing.T) {
+	lt := &logT{TB: t}
+	sink := ToT(lt)
+
+	helpers := lt.helpers
+	_, _, line, _ := runtime.Caller(0)
+	sink.Logger().With(logger.F("id", 7)).Warn("cache miss")
+	sink.Logger().Error("failed")
+
+	lines := lt.logged()
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 forwarded lines, got %v", lines)
+	}
+	if want := fmt.Sprintf("[WARN] cache miss id=7 (at tot_test.go:%d)", line+1); lines[0] != want {
+		t.Errorf("Unexpected line %q, want %q", lines[0], want)
+	}
+	// 调用位置附在第一行，堆栈在其后
+	if want := fmt.Sprintf("[ERROR] failed (at tot_test.go:%d)\ngoroutine ", line+2); !strings.HasPrefix(lines[1], want) {
+		t.Errorf("Unexpected line %q, want prefix %q", lines[1], want)
+	}
+	// sink 回调中的 Helper 标记的是 logger 内部的栈帧，不应调用
+	if lt.helpers != helpers {
+		t.Errorf("Helper called %d times from the sink", lt.helpers-helpers)
+	}
+}
+
+func TestToTStopsAfterTestEnds(t *testing.T) {
+	var sink *TestSink
+	t.Run("inner", func(t *testing.T) {
+		sink = ToT(t)
+	})
+
+	// 直接调用，模拟测试结束后仍在运行的 goroutine；不应 panic
+	if err := sink.WriteEntry(logger.LogEntry{Level: logger.LevelInfo, Message: "late"}); err != nil {
+		t.Errorf("WriteEntry after test end failed: %v", err)
+	}
+}
+
+func TestToTForwardsGlobalLogging(t *testing.T) {
+	lt := &logT{TB: t}
+	ToT(lt)
+	other := NewRecorder(t)
+
+	other.Logger().Info("belongs to recorder")
+	logger.Named("db").Trace("package wide")
+	lines := lt.logged()
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "[TRACE] [db] package wide (at tot_test.go:") {
+		t.Errorf("Unexpected forwarded lines: %v", lines)
+	}
+}
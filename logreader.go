@@ -0,0 +1,240 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// EntryReader reads entries back from output written by this package. It
+// accepts the text format of the console and TextFormatter as well as the
+// JSON lines of JSONFormatter, detected line by line, so mixed files work.
+//
+// Use it like bufio.Scanner:
+//
+//	r := logger.NewEntryReader(file)
+//	for r.Next() {
+//		entry := r.Entry()
+//	}
+//	if err := r.Err(); err != nil { ... }
+//
+// In text files, lines that do not start with a timestamp belong to the
+// preceding entry: from a "goroutine N [" line on they form its stack
+// trace, before that they continue its message. Lines before the first
+// entry are returned as an entry with only Message set. The text format
+// cannot tell fields from the message, so fields stay part of Message;
+// the JSON format keeps them. ANSI color sequences are removed.
+//
+// An entry is complete once the next entry or the end of input is seen.
+// After Next returns false at io.EOF it may be called again to continue
+// with data appended to the input, as when following a growing file.
+type EntryReader struct {
+	r       *bufio.Reader
+	pending *LogEntry // 已读取、仍可能有续行的条目
+	inStack bool      // pending 的续行属于堆栈
+	entry   LogEntry
+	err     error
+}
+
+// NewEntryReader 创建从 r 读取条目的 EntryReader
+func NewEntryReader(r io.Reader) *EntryReader {
+	return &EntryReader{r: bufio.NewReaderSize(r, 64<<10)}
+}
+
+// Next 读取下一条条目，没有更多条目或出错时返回 false
+func (r *EntryReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if len(line) > 0 {
+			if r.addLine(line) {
+				return true
+			}
+		}
+
+		if err != nil {
+			if err != io.EOF {
+				r.err = err
+			}
+			return r.flush()
+		}
+	}
+}
+
+// Entry 返回 Next 读取的条目
+func (r *EntryReader) Entry() LogEntry {
+	return r.entry
+}
+
+// Err 返回读取时遇到的第一个非 io.EOF 错误
+func (r *EntryReader) Err() error {
+	return r.err
+}
+
+// addLine 处理一行，新条目开始且之前有未完成的条目时返回 true
+func (r *EntryReader) addLine(raw []byte) bool {
+	line := string(stripANSI(bytes.TrimRight(raw, "\r\n")))
+
+	if entry, ok := parseEntryLine(line); ok {
+		done := r.flush()
+		r.pending = &entry
+		return done
+	}
+
+	if r.pending == nil {
+		r.pending = &LogEntry{Message: line, StackTrace: []byte{}}
+		return false
+	}
+	if r.inStack || isGoroutineHeader(line) {
+		r.inStack = true
+		r.pending.StackTrace = append(append(r.pending.StackTrace, line...), '\n')
+		return false
+	}
+	r.pending.Message += "\n" + line
+	return false
+}
+
+// flush 将未完成的条目作为结果，没有条目时返回 false
+func (r *EntryReader) flush() bool {
+	if r.pending == nil {
+		return false
+	}
+	r.entry = *r.pending
+	r.pending = nil
+	r.inStack = false
+	return true
+}
+
+// parseEntryLine 解析一行 JSON 或文本格式条目的开头
+func parseEntryLine(line string) (LogEntry, bool) {
+	if strings.HasPrefix(line, "{") {
+		var entry LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err == nil && entry.Level != "" {
+			return entry, true
+		}
+		return LogEntry{}, false
+	}
+	return parseTextLine(line)
+}
+
+// parseTextLine 解析 "时间 [前缀] [LEVEL] [logger] 消息" 形式的行。
+// 前缀和 logger 可以省略：第一个标签是已注册级别时没有前缀，
+// 否则第二个标签像级别名时第一个标签是前缀
+func parseTextLine(line string) (LogEntry, bool) {
+	n := len(TextTimeLayout)
+	if len(line) <= n || line[n] != ' ' {
+		return LogEntry{}, false
+	}
+	timestamp, err := time.ParseInLocation(TextTimeLayout, line[:n], time.Local)
+	if err != nil {
+		return LogEntry{}, false
+	}
+
+	rest := line[n+1:]
+	var tags []string
+	var after []string // 每个标签之后的剩余内容
+	for len(tags) < 3 {
+		tag, remaining, ok := cutTag(rest)
+		if !ok {
+			break
+		}
+		tags = append(tags, tag)
+		after = append(after, remaining)
+		rest = remaining
+	}
+
+	entry := LogEntry{Timestamp: timestamp, StackTrace: []byte{}}
+	var used int
+	switch {
+	case len(tags) >= 1 && isRegisteredLevel(tags[0]):
+		entry.Level, used = tags[0], 1
+	case len(tags) >= 2 && looksLikeLevel(tags[1]):
+		entry.Prefix, entry.Level, used = tags[0], tags[1], 2
+	case len(tags) >= 1 && looksLikeLevel(tags[0]):
+		entry.Level, used = tags[0], 1
+	default:
+		return LogEntry{}, false
+	}
+	if len(tags) > used {
+		entry.Logger = tags[used]
+		used++
+	}
+	entry.Message = after[used-1]
+	return entry, true
+}
+
+// cutTag 切出开头的 "[tag] " 或位于行尾的 "[tag]"
+func cutTag(s string) (tag, rest string, ok bool) {
+	if !strings.HasPrefix(s, "[") {
+		return "", s, false
+	}
+	end := strings.IndexByte(s, ']')
+	if end < 2 {
+		return "", s, false
+	}
+	switch {
+	case end+1 == len(s):
+		return s[1:end], "", true
+	case s[end+1] == ' ':
+		return s[1:end], s[end+2:], true
+	}
+	return "", s, false
+}
+
+func isRegisteredLevel(name string) bool {
+	_, ok := GetLevelSpec(name)
+	return ok && name == strings.ToUpper(name)
+}
+
+// looksLikeLevel 判断标签是否可能是未注册的自定义级别：大写字母、数字和下划线
+func looksLikeLevel(name string) bool {
+	if isRegisteredLevel(name) {
+		return true
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return false
+		}
+	}
+	return name[0] >= 'A' && name[0] <= 'Z'
+}
+
+// isGoroutineHeader 判断是否为 debug.Stack 输出的第一行，如 "goroutine 1 [running]:"
+func isGoroutineHeader(line string) bool {
+	if !strings.HasPrefix(line, "goroutine ") {
+		return false
+	}
+	rest := line[len("goroutine "):]
+	i := 0
+	for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+		i++
+	}
+	return i > 0 && strings.HasPrefix(rest[i:], " [")
+}
+
+// stripANSI 去掉 ANSI 转义序列 ESC [ ... 字母
+func stripANSI(b []byte) []byte {
+	if bytes.IndexByte(b, 0x1b) < 0 {
+		return b
+	}
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		if b[i] == 0x1b && i+1 < len(b) && b[i+1] == '[' {
+			j := i + 2
+			for j < len(b) && !(b[j] >= '@' && b[j] <= '~') {
+				j++
+			}
+			i = j
+			continue
+		}
+		out = append(out, b[i])
+	}
+	return out
+}
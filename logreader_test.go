@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readAllEntries(t *testing.T, r io.Reader) []LogEntry {
+	t.Helper()
+	reader := NewEntryReader(r)
+	var entries []LogEntry
+	for reader.Next() {
+		entries = append(entries, reader.Entry())
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("EntryReader: %v", err)
+	}
+	return entries
+}
+
+func TestEntryReaderExampleLog(t *testing.T) {
+	file, err := os.Open("examples/example.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	entries := readAllEntries(t, file)
+	if len(entries) != 5 {
+		t.Fatalf("got %d entries, want 5", len(entries))
+	}
+
+	warn := entries[1]
+	if warn.Level != LevelWarn || warn.Prefix != "ExampleApp" || warn.Message != "High memory usage detected: 85%!\n(MISSING)" {
+		t.Errorf("multi-line message entry = %+v", warn)
+	}
+
+	failed := entries[3]
+	if failed.Level != LevelError || failed.Message != "Database connection timeout" {
+		t.Errorf("error entry = %+v", failed)
+	}
+	stack := string(failed.StackTrace)
+	if !strings.HasPrefix(stack, "goroutine 1 [running]:\n") || !strings.HasSuffix(stack, "main.go:54 +0x2ac\n") {
+		t.Errorf("stack trace = %q", stack)
+	}
+
+	want := time.Date(2026, 2, 12, 18, 28, 0, 0, time.Local)
+	if !entries[4].Timestamp.Equal(want) || entries[4].Message != "Request processed successfully" {
+		t.Errorf("last entry = %+v", entries[4])
+	}
+}
+
+func TestEntryReaderRoundTrip(t *testing.T) {
+	timestamp := time.Date(2024, 5, 6, 7, 8, 9, 0, time.Local)
+	written := []LogEntry{
+		{Timestamp: timestamp, Level: LevelInfo, Prefix: "svc", Logger: "api", Message: "request done status=200"},
+		{Timestamp: timestamp, Level: LevelError, Message: "failed", StackTrace: []byte("goroutine 7 [running]:\nmain.main()\n\t/app/main.go:10 +0x1\n")},
+		{Timestamp: timestamp, Level: LevelDebug, Prefix: "svc", Message: ""},
+		{Timestamp: timestamp, Level: LevelWarn, Logger: "db", Message: "slow query", Fields: []Field{F("ms", "250")}},
+	}
+
+	var text, jsonl bytes.Buffer
+	for _, entry := range written {
+		data, _ := TextFormatter{}.Format(entry)
+		text.Write(data)
+		data, _ = JSONFormatter{}.Format(entry)
+		jsonl.Write(data)
+	}
+
+	for name, input := range map[string]*bytes.Buffer{"text": &text, "json": &jsonl} {
+		entries := readAllEntries(t, input)
+		if len(entries) != len(written) {
+			t.Fatalf("%s: got %d entries, want %d", name, len(entries), len(written))
+		}
+		for i, got := range entries {
+			want := written[i]
+			if name == "text" {
+				want.Message += formatFields(want.Fields)
+			}
+			if !got.Timestamp.Equal(want.Timestamp) || got.Level != want.Level || got.Prefix != want.Prefix ||
+				got.Logger != want.Logger || got.Message != want.Message || string(got.StackTrace) != string(want.StackTrace) {
+				t.Errorf("%s entry %d = %+v, want %+v", name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestEntryReaderMixedAndColored(t *testing.T) {
+	input := "orphan line\n" +
+		"2024/05/06 07:08:09 [" + colorGreen + "INFO" + colorReset + "] colored\r\n" +
+		`{"time":"2024-05-06T07:08:10Z","level":"WARN","msg":"from json","fields":{"n":1}}` + "\n" +
+		"2024/05/06 07:08:11 [app] [AUDIT] custom level\n" +
+		"2024/05/06 07:08:12 [INFO] [worker] no trailing newline"
+
+	entries := readAllEntries(t, strings.NewReader(input))
+	got := make([]string, len(entries))
+	for i, e := range entries {
+		got[i] = e.Prefix + "|" + e.Level + "|" + e.Logger + "|" + e.Message
+	}
+	want := []string{
+		"||" + "|orphan line",
+		"|INFO||colored",
+		"|WARN||from json",
+		"app|AUDIT||custom level",
+		"|INFO|worker|no trailing newline",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("entries:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if len(entries[2].Fields) != 1 || entries[2].Fields[0].Key != "n" {
+		t.Errorf("json fields = %+v", entries[2].Fields)
+	}
+}
+
+// 输入增长后可以继续读取
+func TestEntryReaderResumesAfterEOF(t *testing.T) {
+	var buf bytes.Buffer
+	reader := NewEntryReader(&buf)
+
+	buf.WriteString("2024/05/06 07:08:09 [INFO] first\n")
+	if !reader.Next() || reader.Entry().Message != "first" {
+		t.Fatalf("first entry = %+v", reader.Entry())
+	}
+	if reader.Next() {
+		t.Fatal("Next should return false at EOF")
+	}
+
+	buf.WriteString("2024/05/06 07:08:10 [INFO] second\n")
+	if !reader.Next() || reader.Entry().Message != "second" {
+		t.Fatalf("second entry = %+v", reader.Entry())
+	}
+}
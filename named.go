@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"sync"
+)
+
+// Logger is a named logger. It shares outputs and channels with the package
+// level functions but can have its own minimum level.
+type Logger struct {
+	name   string
+	fields []Field
+	sinks  []Sink // 只接收该 logger 条目的 sink，不受输出级别限制
+}
+
+var (
+	// std 是包级日志函数使用的匿名 logger
+	std = &Logger{}
+
+	namedLoggers map[string]*Logger
+	namedMutex   sync.Mutex
+)
+
+func init() {
+	namedLoggers = make(map[string]*Logger)
+}
+
+// Named 创建或获取指定名称的 logger
+func Named(name string) *Logger {
+	namedMutex.Lock()
+	defer namedMutex.Unlock()
+
+	if l, exists := namedLoggers[name]; exists {
+		return l
+	}
+
+	l := &Logger{name: name}
+	namedLoggers[name] = l
+	return l
+}
+
+// LoggerNames 返回所有已创建的命名 logger 名称
+func LoggerNames() []string {
+	namedMutex.Lock()
+	defer namedMutex.Unlock()
+
+	names := make([]string, 0, len(namedLoggers))
+	for name := range namedLoggers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Name 返回 logger 名称
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// With returns a logger with the same name that attaches the given fields to
+// every entry in addition to the fields of l
+func (l *Logger) With(fields ...Field) *Logger {
+	merged := make([]Field, 0, len(l.fields)+len(fields))
+	merged = append(merged, l.fields...)
+	merged = append(merged, fields...)
+	return &Logger{name: l.name, fields: merged, sinks: l.sinks}
+}
+
+// With returns an unnamed logger that attaches the given fields to every entry
+func With(fields ...Field) *Logger {
+	return std.With(fields...)
+}
+
+// WithSink returns a logger with the same name and fields that also writes
+// every entry it logs to s, after redaction and whatever the output level.
+// Other loggers never write to s, and loggers derived from the result with
+// With keep it. Sampling still applies. s is not closed by the package.
+func (l *Logger) WithSink(s Sink) *Logger {
+	sinks := make([]Sink, 0, len(l.sinks)+1)
+	sinks = append(sinks, l.sinks...)
+	sinks = append(sinks, s)
+	return &Logger{name: l.name, fields: l.fields, sinks: sinks}
+}
+
+// WithSink returns an unnamed logger that also writes its entries to s
+func WithSink(s Sink) *Logger {
+	return std.WithSink(s)
+}
+
+// SetLevel sets the minimum level of this logger, overriding the global level
+func (l *Logger) SetLevel(level string) error {
+	return SetLoggerLevel(l.name, level)
+}
+
+// GetLevel 返回该 logger 生效的最低级别名称
+func (l *Logger) GetLevel() string {
+	return GetLoggerLevel(l.name)
+}
+
+// Trace prints log message with TRACE level
+func (l *Logger) Trace(format string, args ...any) {
+	l.logf(specTrace, format, args...)
+}
+
+// Debug prints log message with DEBUG level
+func (l *Logger) Debug(format string, args ...any) {
+	l.logf(specDebug, format, args...)
+}
+
+// Info prints log message with INFO level
+func (l *Logger) Info(format string, args ...any) {
+	l.logf(specInfo, format, args...)
+}
+
+// Warn prints log message with WARN level
+func (l *Logger) Warn(format string, args ...any) {
+	l.logf(specWarn, format, args...)
+}
+
+// Error prints log message with ERROR level
+func (l *Logger) Error(format string, args ...any) {
+	l.logf(specError, format, args...)
+}
+
+// Fatal prints log message with FATAL level, closes the registered sinks so
+// that queued entries are sent, and calls os.Exit(1)
+func (l *Logger) Fatal(format string, args ...any) {
+	l.logf(specFatal, format, args...)
+	exit(1)
+}
+
+// Tracew logs msg with TRACE level and the given fields
+func (l *Logger) Tracew(msg string, fields ...Field) {
+	l.logw(specTrace, msg, fields...)
+}
+
+// Debugw logs msg with DEBUG level and the given fields
+func (l *Logger) Debugw(msg string, fields ...Field) {
+	l.logw(specDebug, msg, fields...)
+}
+
+// Infow logs msg with INFO level and the given fields
+func (l *Logger) Infow(msg string, fields ...Field) {
+	l.logw(specInfo, msg, fields...)
+}
+
+// Warnw logs msg with WARN level and the given fields
+func (l *Logger) Warnw(msg string, fields ...Field) {
+	l.logw(specWarn, msg, fields...)
+}
+
+// Errorw logs msg with ERROR level and the given fields
+func (l *Logger) Errorw(msg string, fields ...Field) {
+	l.logw(specError, msg, fields...)
+}
+
+// Log prints log message with the given level
+func (l *Logger) Log(level string, format string, args ...any) {
+	l.logf(resolveLevel(level), format, args...)
+}
@@ -0,0 +1,341 @@
+package logger
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Framing 选择网络 sink 的分帧方式
+type Framing int
+
+// Framing constants
+const (
+	// FrameNewline 每帧以换行结尾。JSONFormatter 以外的内容中 \ 转义为 \\、换行转义为 \n，
+	// 多行条目（如带堆栈的文本）因此只占一帧；JSON 输出不含换行，原样发送
+	FrameNewline      Framing = iota
+	FrameLengthPrefix         // 每帧前加 4 字节大端长度
+)
+
+// NetworkConfig 配置网络 sink
+type NetworkConfig struct {
+	Network   string    // "tcp"、"udp"、"unix" 或 "unixgram"
+	Address   string    // 远程地址或 unix socket 路径
+	Framing   Framing   // 分帧方式，默认 FrameNewline
+	Formatter Formatter // 条目编码，默认 JSONFormatter
+
+	SpoolSize     int    // 内存缓冲的最大帧数，默认 10000，满时丢弃最旧的帧
+	SpoolDir      string // 非空时改用磁盘缓冲，文件位于该目录
+	SpoolMaxBytes int64  // 磁盘缓冲文件大小上限，默认 64MB，满时丢弃新帧
+
+	MinBackoff   time.Duration // 首次重连等待时间，默认 100ms
+	MaxBackoff   time.Duration // 重连等待时间上限，默认 30s
+	DialTimeout  time.Duration // 默认 5s
+	WriteTimeout time.Duration // 默认 5s
+}
+
+// NetworkSinkStats 描述网络 sink 的状态
+type NetworkSinkStats struct {
+	Connected bool
+	Spooled   int    // 等待发送的帧数
+	Sent      uint64 // 已发送的帧数
+	Dropped   uint64 // 因缓冲区满或超过数据报上限而丢弃的帧数
+}
+
+// NetworkSink writes framed entries to a TCP, UDP or unix socket.
+// WriteEntry never blocks on the network: entries are queued in the spool
+// and sent by a background goroutine that reconnects with exponential
+// backoff, so collector restarts lose nothing as long as the spool has room.
+type NetworkSink struct {
+	config NetworkConfig
+
+	mu    sync.Mutex
+	spool spool
+
+	wake chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	connected atomic.Bool
+	sent      atomic.Uint64
+	dropped   atomic.Uint64
+	closeOnce sync.Once
+}
+
+// supportedNetwork 判断 NetworkSink 是否支持该网络类型
+func supportedNetwork(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+		return true
+	}
+	return false
+}
+
+// NewNetworkSink creates the sink and starts connecting in the background
+func NewNetworkSink(config NetworkConfig) (*NetworkSink, error) {
+	if !supportedNetwork(config.Network) {
+		return nil, fmt.Errorf("unsupported network %q", config.Network)
+	}
+	if config.Formatter == nil {
+		config.Formatter = JSONFormatter{}
+	}
+	if config.SpoolSize <= 0 {
+		config.SpoolSize = 10000
+	}
+	if config.SpoolMaxBytes <= 0 {
+		config.SpoolMaxBytes = 64 << 20
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = 100 * time.Millisecond
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = 30 * time.Second
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = 5 * time.Second
+	}
+
+	s := &NetworkSink{
+		config: config,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	if config.SpoolDir != "" {
+		name := fmt.Sprintf("%s-%s.spool", config.Network, sanitizeFileName(config.Address))
+		ds, err := newDiskSpool(config.SpoolDir, name, config.SpoolMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("open spool: %w", err)
+		}
+		s.spool = ds
+	} else {
+		s.spool = newMemorySpool(config.SpoolSize)
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	return s, nil
+}
+
+// WriteEntry 编码条目并放入发送队列
+func (s *NetworkSink) WriteEntry(entry LogEntry) error {
+	data, err := s.config.Formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	frame := s.frame(data)
+
+	s.mu.Lock()
+	if !s.spool.push(frame) {
+		s.dropped.Add(1)
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Stats 返回当前状态
+func (s *NetworkSink) Stats() NetworkSinkStats {
+	s.mu.Lock()
+	spooled := s.spool.len()
+	s.mu.Unlock()
+
+	return NetworkSinkStats{
+		Connected: s.connected.Load(),
+		Spooled:   spooled,
+		Sent:      s.sent.Load(),
+		Dropped:   s.dropped.Load(),
+	}
+}
+
+// Close stops the background sender. Queued frames are sent if the
+// connection is up and the drain finishes within WriteTimeout; frames left in
+// a disk spool are sent on the next start.
+func (s *NetworkSink) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+
+		s.mu.Lock()
+		err = s.spool.close()
+		s.mu.Unlock()
+	})
+	return err
+}
+
+// frame 按配置的分帧方式封装数据
+func (s *NetworkSink) frame(data []byte) []byte {
+	if s.config.Framing == FrameLengthPrefix {
+		frame := make([]byte, 4, 4+len(data))
+		binary.BigEndian.PutUint32(frame, uint32(len(data)))
+		return append(frame, data...)
+	}
+
+	// 多行内容（如带堆栈的文本）转义换行，保证一条条目只占一帧；反斜杠一并转义，
+	// 接收端才能区分原文中的 "\n" 和换行。JSON 输出不含换行，转义反而会破坏它
+	line := stripNewline(string(data))
+	if _, isJSON := s.config.Formatter.(JSONFormatter); !isJSON {
+		line = newlineEscaper.Replace(line)
+	}
+	return append([]byte(line), '\n')
+}
+
+// newlineEscaper 转义 FrameNewline 帧内容中的反斜杠和换行
+var newlineEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// run 是后台发送循环
+func (s *NetworkSink) run() {
+	defer s.wg.Done()
+
+	var conn net.Conn
+	var broken chan struct{} // 对端关闭连接时被关闭
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+		s.connected.Store(false)
+	}()
+
+	backoff := s.config.MinBackoff
+	var drainDeadline time.Time
+
+	for {
+		closing := false
+		select {
+		case <-s.done:
+			closing = true
+			if drainDeadline.IsZero() {
+				drainDeadline = time.Now().Add(s.config.WriteTimeout)
+			}
+		default:
+		}
+
+		s.mu.Lock()
+		frame, id, ok := s.spool.peek()
+		s.mu.Unlock()
+
+		if !ok {
+			if closing {
+				return
+			}
+			select {
+			case <-s.wake:
+			case <-s.done:
+			case <-broken:
+				conn.Close()
+				conn, broken = nil, nil
+				s.connected.Store(false)
+			}
+			continue
+		}
+
+		if conn == nil {
+			// 关闭过程中不再重连，剩余帧留给磁盘缓冲
+			if closing {
+				return
+			}
+
+			var err error
+			conn, err = net.DialTimeout(s.config.Network, s.config.Address, s.config.DialTimeout)
+			if err != nil {
+				conn = nil
+				select {
+				case <-time.After(backoff):
+				case <-s.done:
+				}
+				backoff *= 2
+				if backoff > s.config.MaxBackoff {
+					backoff = s.config.MaxBackoff
+				}
+				continue
+			}
+			backoff = s.config.MinBackoff
+			s.connected.Store(true)
+			broken = watchPeerClose(conn)
+		}
+
+		if closing && time.Now().After(drainDeadline) {
+			return
+		}
+
+		// 对端已关闭时写入可能仍然成功但数据会丢失，先检查连接状态
+		select {
+		case <-broken:
+			conn.Close()
+			conn, broken = nil, nil
+			s.connected.Store(false)
+			continue
+		default:
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(s.config.WriteTimeout))
+		if _, err := conn.Write(frame); err != nil {
+			if errors.Is(err, syscall.EMSGSIZE) {
+				// 超过数据报上限的帧重试也不会成功，丢弃后继续发送后面的帧
+				s.mu.Lock()
+				s.spool.pop(id)
+				s.mu.Unlock()
+				s.dropped.Add(1)
+				warnf("Log network sink dropped a %d-byte frame: %v", len(frame), err)
+				continue
+			}
+			conn.Close()
+			conn, broken = nil, nil
+			s.connected.Store(false)
+			continue
+		}
+
+		s.mu.Lock()
+		s.spool.pop(id)
+		s.mu.Unlock()
+		s.sent.Add(1)
+	}
+}
+
+// watchPeerClose 持续读取流式连接，在对端关闭或连接出错时关闭返回的 channel
+// 日志收集端不会回写数据，读取到的内容直接丢弃；数据报连接返回 nil
+func watchPeerClose(conn net.Conn) chan struct{} {
+	switch conn.(type) {
+	case *net.UDPConn:
+		return nil
+	case *net.UnixConn:
+		if conn.RemoteAddr().Network() == "unixgram" {
+			return nil
+		}
+	}
+
+	broken := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, conn)
+		close(broken)
+	}()
+	return broken
+}
+
+// sanitizeFileName 将地址转换为可用作文件名的字符串
+func sanitizeFileName(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '.':
+		default:
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,356 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// acceptLines 接受连接并将每行内容发送到返回的 channel
+func acceptLines(ln net.Listener) <-chan string {
+	lines := make(chan string, 64)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+	return lines
+}
+
+func receiveLine(t *testing.T, lines <-chan string) LogEntry {
+	t.Helper()
+
+	select {
+	case line := <-lines:
+		var entry LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", line, err)
+		}
+		return entry
+	case <-time.After(3 * time.Second):
+		t.Fatal("Timeout waiting for line")
+	}
+	return LogEntry{}
+}
+
+func waitStats(t *testing.T, sink *NetworkSink, cond func(NetworkSinkStats) bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond(sink.Stats()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Condition not met, stats: %+v", sink.Stats())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNetworkSinkNewlineJSON(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer ln.Close()
+	lines := acceptLines(ln)
+
+	sink, err := NewNetworkSink(NetworkConfig{Network: "tcp", Address: ln.Addr().String()})
+	if err != nil {
+		t.Fatalf("NewNetworkSink failed: %v", err)
+	}
+	AddSink("network-test", sink)
+	defer RemoveSink("network-test")
+
+	Named("net").With(F("n", 1)).Info("over the wire")
+
+	entry := receiveLine(t, lines)
+	if entry.Message != "over the wire" || entry.Logger != "net" || len(entry.Fields) != 1 {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+}
+
+func TestNetworkSinkLengthPrefix(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer ln.Close()
+
+	frames := make(chan string, 4)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var header [4]byte
+			if _, err := io.ReadFull(conn, header[:]); err != nil {
+				return
+			}
+			data := make([]byte, binary.BigEndian.Uint32(header[:]))
+			if _, err := io.ReadFull(conn, data); err != nil {
+				return
+			}
+			frames <- string(data)
+		}
+	}()
+
+	sink, err := NewNetworkSink(NetworkConfig{
+		Network:   "tcp",
+		Address:   ln.Addr().String(),
+		Framing:   FrameLengthPrefix,
+		Formatter: TextFormatter{},
+	})
+	if err != nil {
+		t.Fatalf("NewNetworkSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	timestamp := time.Date(2026, 2, 12, 18, 28, 0, 0, time.Local)
+	sink.WriteEntry(LogEntry{Timestamp: timestamp, Level: LevelError, Prefix: "App", Message: "boom", StackTrace: []byte("stack\n")})
+
+	select {
+	case frame := <-frames:
+		if frame != "2026/02/12 18:28:00 [App] [ERROR] boom\nstack\n" {
+			t.Errorf("Unexpected frame %q", frame)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Timeout waiting for frame")
+	}
+}
+
+func TestNetworkSinkNewlineEscapesStack(t *testing.T) {
+	s := &NetworkSink{config: NetworkConfig{Framing: FrameNewline, Formatter: TextFormatter{}}}
+	timestamp := time.Date(2026, 2, 12, 18, 28, 0, 0, time.Local)
+	entry := LogEntry{Timestamp: timestamp, Level: LevelError, Message: `path C:\new`, StackTrace: []byte("goroutine 1\nmain.main()\n")}
+	data, _ := TextFormatter{}.Format(entry)
+
+	// 原文中的反斜杠先被转义，与换行的转义可以区分
+	frame := string(s.frame(data))
+	want := `2026/02/12 18:28:00 [ERROR] path C:\\new\ngoroutine 1\nmain.main()` + "\n"
+	if frame != want {
+		t.Errorf("Expected one frame %q, got %q", want, frame)
+	}
+
+	// JSON 不含换行，反斜杠保持原样
+	s.config.Formatter = JSONFormatter{}
+	data, _ = JSONFormatter{}.Format(entry)
+	var decoded LogEntry
+	if err := json.Unmarshal(s.frame(data), &decoded); err != nil || decoded.Message != entry.Message {
+		t.Errorf("JSON frame changed: %v, %q", err, decoded.Message)
+	}
+}
+
+func TestNetworkSinkSpoolsWhileCollectorRestarts(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	addr := ln.Addr().String()
+
+	// 第一个收集端：接收一行后连同监听一起关闭
+	first := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		scanner := bufio.NewScanner(conn)
+		if scanner.Scan() {
+			first <- scanner.Text()
+		}
+		conn.Close()
+	}()
+
+	sink, err := NewNetworkSink(NetworkConfig{
+		Network:    "tcp",
+		Address:    addr,
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewNetworkSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	sink.WriteEntry(LogEntry{Level: LevelInfo, Message: "before"})
+	select {
+	case <-first:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Timeout waiting for first collector")
+	}
+	ln.Close()
+	waitStats(t, sink, func(s NetworkSinkStats) bool { return !s.Connected })
+
+	for _, msg := range []string{"during-1", "during-2", "during-3"} {
+		sink.WriteEntry(LogEntry{Level: LevelInfo, Message: msg})
+	}
+	waitStats(t, sink, func(s NetworkSinkStats) bool { return s.Spooled == 3 })
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("Cannot rebind %s: %v", addr, err)
+	}
+	defer ln.Close()
+	lines := acceptLines(ln)
+
+	for _, want := range []string{"during-1", "during-2", "during-3"} {
+		if entry := receiveLine(t, lines); entry.Message != want {
+			t.Errorf("Expected %q, got %q", want, entry.Message)
+		}
+	}
+}
+
+func TestNetworkSinkDiskSpool(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "collector.sock")
+	spoolDir := filepath.Join(dir, "spool")
+
+	config := NetworkConfig{
+		Network:    "unix",
+		Address:    socket,
+		SpoolDir:   spoolDir,
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 50 * time.Millisecond,
+	}
+
+	// 收集端不存在，条目写入磁盘缓冲
+	sink, err := NewNetworkSink(config)
+	if err != nil {
+		t.Fatalf("NewNetworkSink failed: %v", err)
+	}
+	for _, msg := range []string{"a", "b"} {
+		sink.WriteEntry(LogEntry{Level: LevelInfo, Message: msg})
+	}
+	sink.Close()
+
+	files, _ := filepath.Glob(filepath.Join(spoolDir, "*.spool"))
+	if len(files) != 1 {
+		t.Fatalf("Expected one spool file, got %d", len(files))
+	}
+
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer ln.Close()
+	lines := acceptLines(ln)
+
+	// 重新创建 sink 后重放磁盘缓冲
+	sink, err = NewNetworkSink(config)
+	if err != nil {
+		t.Fatalf("NewNetworkSink failed: %v", err)
+	}
+	defer sink.Close()
+	sink.WriteEntry(LogEntry{Level: LevelInfo, Message: "c"})
+
+	for _, want := range []string{"a", "b", "c"} {
+		if entry := receiveLine(t, lines); entry.Message != want {
+			t.Errorf("Expected %q, got %q", want, entry.Message)
+		}
+	}
+	waitStats(t, sink, func(s NetworkSinkStats) bool { return s.Spooled == 0 })
+}
+
+func TestMemorySpoolDropsOldest(t *testing.T) {
+	s := newMemorySpool(2)
+	s.push([]byte("1"))
+	_, id, _ := s.peek()
+	s.push([]byte("2"))
+	if s.push([]byte("3")) {
+		t.Error("push should report a drop when full")
+	}
+
+	// 队首已被丢弃，旧编号不能移除新队首
+	s.pop(id)
+	if frame, _, _ := s.peek(); string(frame) != "2" {
+		t.Errorf("Expected '2' at head, got %q", frame)
+	}
+}
+
+func TestDiskSpoolResumesFromOffset(t *testing.T) {
+	dir := t.TempDir()
+	s, err := newDiskSpool(dir, "test.spool", 0)
+	if err != nil {
+		t.Fatalf("newDiskSpool failed: %v", err)
+	}
+	for _, frame := range []string{"a", "b", "c"} {
+		s.push([]byte(frame))
+	}
+	_, id, _ := s.peek()
+	s.pop(id)
+	s.close()
+
+	// 重启后不重放已发送的帧
+	s, err = newDiskSpool(dir, "test.spool", 0)
+	if err != nil {
+		t.Fatalf("newDiskSpool failed: %v", err)
+	}
+	if s.len() != 2 {
+		t.Errorf("Expected 2 frames after restart, got %d", s.len())
+	}
+	for _, want := range []string{"b", "c"} {
+		frame, id, _ := s.peek()
+		if string(frame) != want {
+			t.Errorf("Expected %q at head, got %q", want, frame)
+		}
+		s.pop(id)
+	}
+	s.close()
+
+	// 全部发送后重启为空队列
+	s, err = newDiskSpool(dir, "test.spool", 0)
+	if err != nil {
+		t.Fatalf("newDiskSpool failed: %v", err)
+	}
+	defer s.close()
+	if s.len() != 0 {
+		t.Errorf("Expected empty spool, got %d frames", s.len())
+	}
+}
+
+func TestNetworkSinkDropsOversizedDatagram(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket failed: %v", err)
+	}
+	defer pc.Close()
+
+	sink, err := NewNetworkSink(NetworkConfig{Network: "udp", Address: pc.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("NewNetworkSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	// 超过 UDP 上限的帧被丢弃，不阻塞后面的帧
+	sink.WriteEntry(LogEntry{Level: LevelInfo, Message: strings.Repeat("x", 70000)})
+	sink.WriteEntry(LogEntry{Level: LevelInfo, Message: "after"})
+
+	pc.SetReadDeadline(time.Now().Add(3 * time.Second))
+	buf := make([]byte, 2048)
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	var entry LogEntry
+	if err := json.Unmarshal(buf[:n], &entry); err != nil || entry.Message != "after" {
+		t.Errorf("Unexpected datagram %q: %v", buf[:n], err)
+	}
+	waitStats(t, sink, func(s NetworkSinkStats) bool { return s.Dropped == 1 && s.Sent == 1 && s.Spooled == 0 })
+}
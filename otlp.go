@@ -0,0 +1,467 @@
+package logger
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 携带链路信息的字段名，值为十六进制字符串
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// otlpScopeName 是导出记录的 InstrumentationScope 名称
+const otlpScopeName = "repo.smlk.org/logger"
+
+// OTLPEncoder encodes entries as an OTLP ExportLogsServiceRequest, either as
+// protobuf (default) or as OTLP/JSON.
+//
+// Entries are grouped into one ResourceLogs per prefix with service.name set
+// to the prefix. Fields become attributes, except trace_id and span_id which
+// fill the record's trace context when they are valid hex IDs.
+type OTLPEncoder struct {
+	JSON               bool              // 使用 OTLP/JSON 编码
+	ResourceAttributes map[string]string // 附加的资源属性
+}
+
+// ContentType 实现 BatchEncoder
+func (e OTLPEncoder) ContentType() string {
+	if e.JSON {
+		return "application/json"
+	}
+	return "application/x-protobuf"
+}
+
+// Encode 实现 BatchEncoder
+func (e OTLPEncoder) Encode(entries []LogEntry) ([]byte, error) {
+	groups := e.group(entries)
+	if e.JSON {
+		return e.encodeJSON(groups)
+	}
+	return e.encodeProto(groups), nil
+}
+
+// otlpValue 是 AnyValue 的中间表示
+type otlpValue struct {
+	kind byte // 's' string, 'b' bool, 'i' int, 'd' double
+	s    string
+	b    bool
+	i    int64
+	d    float64
+}
+
+type otlpAttr struct {
+	key   string
+	value otlpValue
+}
+
+type otlpRecord struct {
+	time     uint64
+	observed uint64
+	severity int
+	text     string
+	body     string
+	attrs    []otlpAttr
+	traceID  []byte
+	spanID   []byte
+}
+
+type otlpResource struct {
+	attrs   []otlpAttr
+	records []otlpRecord
+}
+
+// group 按前缀分组并转换为 OTLP 数据模型
+func (e OTLPEncoder) group(entries []LogEntry) []*otlpResource {
+	var resources []*otlpResource
+	index := make(map[string]*otlpResource)
+	observed := uint64(time.Now().UnixNano())
+
+	for _, entry := range entries {
+		res, ok := index[entry.Prefix]
+		if !ok {
+			res = &otlpResource{attrs: e.resourceAttrs(entry.Prefix)}
+			index[entry.Prefix] = res
+			resources = append(resources, res)
+		}
+		res.records = append(res.records, otlpRecordFrom(entry, observed))
+	}
+	return resources
+}
+
+// resourceAttrs 生成资源属性，service.name 默认取前缀
+func (e OTLPEncoder) resourceAttrs(prefix string) []otlpAttr {
+	keys := make([]string, 0, len(e.ResourceAttributes))
+	for k := range e.ResourceAttributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var attrs []otlpAttr
+	if _, ok := e.ResourceAttributes["service.name"]; !ok && prefix != "" {
+		attrs = append(attrs, otlpAttr{"service.name", otlpValue{kind: 's', s: prefix}})
+	}
+	for _, k := range keys {
+		attrs = append(attrs, otlpAttr{k, otlpValue{kind: 's', s: e.ResourceAttributes[k]}})
+	}
+	return attrs
+}
+
+// otlpRecordFrom 将条目转换为 LogRecord
+func otlpRecordFrom(entry LogEntry, observed uint64) otlpRecord {
+	spec := resolveLevel(entry.Level)
+	severity := spec.Severity
+	if severity < 1 {
+		severity = 1
+	} else if severity > 24 {
+		severity = 24
+	}
+
+	record := otlpRecord{
+		observed: observed,
+		severity: severity,
+		text:     entry.Level,
+		body:     stripNewline(entry.Message),
+	}
+	if !entry.Timestamp.IsZero() {
+		record.time = uint64(entry.Timestamp.UnixNano())
+	}
+
+	for _, f := range entry.Fields {
+		switch f.Key {
+		case TraceIDKey:
+			if id := otlpID(f.String(), 16); id != nil {
+				record.traceID = id
+				continue
+			}
+		case SpanIDKey:
+			if id := otlpID(f.String(), 8); id != nil {
+				record.spanID = id
+				continue
+			}
+		}
+		record.attrs = append(record.attrs, otlpAttr{f.Key, otlpValueOf(f.Any())})
+	}
+
+	if entry.Logger != "" {
+		record.attrs = append(record.attrs, otlpAttr{"logger.name", otlpValue{kind: 's', s: entry.Logger}})
+	}
+	if entry.File != "" {
+		record.attrs = append(record.attrs,
+			otlpAttr{"code.filepath", otlpValue{kind: 's', s: entry.File}},
+			otlpAttr{"code.lineno", otlpValue{kind: 'i', i: int64(entry.Line)}})
+	}
+	if entry.Function != "" {
+		record.attrs = append(record.attrs, otlpAttr{"code.function", otlpValue{kind: 's', s: entry.Function}})
+	}
+	if spec.Stack == StackPrint && len(entry.StackTrace) > 0 {
+		record.attrs = append(record.attrs, otlpAttr{"exception.stacktrace", otlpValue{kind: 's', s: string(entry.StackTrace)}})
+	}
+	return record
+}
+
+// otlpID 解析指定字节长度的十六进制 ID，全零或格式错误时返回 nil
+func otlpID(s string, size int) []byte {
+	id, err := hex.DecodeString(s)
+	if err != nil || len(id) != size {
+		return nil
+	}
+	for _, b := range id {
+		if b != 0 {
+			return id
+		}
+	}
+	return nil
+}
+
+// otlpValueOf 将字段值映射为 AnyValue
+func otlpValueOf(v any) otlpValue {
+	switch x := v.(type) {
+	case string:
+		return otlpValue{kind: 's', s: x}
+	case bool:
+		return otlpValue{kind: 'b', b: x}
+	case int:
+		return otlpValue{kind: 'i', i: int64(x)}
+	case int8:
+		return otlpValue{kind: 'i', i: int64(x)}
+	case int16:
+		return otlpValue{kind: 'i', i: int64(x)}
+	case int32:
+		return otlpValue{kind: 'i', i: int64(x)}
+	case int64:
+		return otlpValue{kind: 'i', i: x}
+	case uint8:
+		return otlpValue{kind: 'i', i: int64(x)}
+	case uint16:
+		return otlpValue{kind: 'i', i: int64(x)}
+	case uint32:
+		return otlpValue{kind: 'i', i: int64(x)}
+	case uint64:
+		if x <= math.MaxInt64 {
+			return otlpValue{kind: 'i', i: int64(x)}
+		}
+	case float32:
+		return otlpValue{kind: 'd', d: float64(x)}
+	case float64:
+		return otlpValue{kind: 'd', d: x}
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return otlpValue{kind: 'i', i: i}
+		}
+		if d, err := x.Float64(); err == nil {
+			return otlpValue{kind: 'd', d: d}
+		}
+	}
+	return otlpValue{kind: 's', s: fmt.Sprint(v)}
+}
+
+// ---- OTLP/JSON ----
+
+func (v otlpValue) MarshalJSON() ([]byte, error) {
+	switch v.kind {
+	case 'b':
+		return json.Marshal(map[string]bool{"boolValue": v.b})
+	case 'i':
+		// OTLP/JSON 中 int64 编码为字符串
+		return json.Marshal(map[string]string{"intValue": strconv.FormatInt(v.i, 10)})
+	case 'd':
+		if math.IsNaN(v.d) || math.IsInf(v.d, 0) {
+			return json.Marshal(map[string]string{"stringValue": fmt.Sprint(v.d)})
+		}
+		return json.Marshal(map[string]float64{"doubleValue": v.d})
+	default:
+		return json.Marshal(map[string]string{"stringValue": v.s})
+	}
+}
+
+func (a otlpAttr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}{a.key, a.value})
+}
+
+func (e OTLPEncoder) encodeJSON(resources []*otlpResource) ([]byte, error) {
+	type record struct {
+		TimeUnixNano         string     `json:"timeUnixNano,omitempty"`
+		ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+		SeverityNumber       int        `json:"severityNumber"`
+		SeverityText         string     `json:"severityText"`
+		Body                 otlpValue  `json:"body"`
+		Attributes           []otlpAttr `json:"attributes,omitempty"`
+		TraceID              string     `json:"traceId,omitempty"`
+		SpanID               string     `json:"spanId,omitempty"`
+	}
+	type scopeLogs struct {
+		Scope      map[string]string `json:"scope"`
+		LogRecords []record          `json:"logRecords"`
+	}
+	type resourceLogs struct {
+		Resource  map[string][]otlpAttr `json:"resource"`
+		ScopeLogs []scopeLogs           `json:"scopeLogs"`
+	}
+
+	request := struct {
+		ResourceLogs []resourceLogs `json:"resourceLogs"`
+	}{}
+
+	for _, res := range resources {
+		records := make([]record, 0, len(res.records))
+		for _, r := range res.records {
+			rec := record{
+				ObservedTimeUnixNano: strconv.FormatUint(r.observed, 10),
+				SeverityNumber:       r.severity,
+				SeverityText:         r.text,
+				Body:                 otlpValue{kind: 's', s: r.body},
+				Attributes:           r.attrs,
+				TraceID:              hex.EncodeToString(r.traceID),
+				SpanID:               hex.EncodeToString(r.spanID),
+			}
+			if r.time != 0 {
+				rec.TimeUnixNano = strconv.FormatUint(r.time, 10)
+			}
+			records = append(records, rec)
+		}
+
+		request.ResourceLogs = append(request.ResourceLogs, resourceLogs{
+			Resource: map[string][]otlpAttr{"attributes": res.attrs},
+			ScopeLogs: []scopeLogs{{
+				Scope:      map[string]string{"name": otlpScopeName},
+				LogRecords: records,
+			}},
+		})
+	}
+
+	return json.Marshal(request)
+}
+
+// ---- OTLP protobuf ----
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+func appendTag(b []byte, field int, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	b = appendTag(b, field, wireVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+func appendFixed64Field(b []byte, field int, v uint64) []byte {
+	b = appendTag(b, field, wireFixed64)
+	return binary.LittleEndian.AppendUint64(b, v)
+}
+
+func appendBytesField(b []byte, field int, data []byte) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendStringField(b []byte, field int, s string) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// AnyValue: string_value=1 bool_value=2 int_value=3 double_value=4
+func (v otlpValue) appendProto(b []byte) []byte {
+	switch v.kind {
+	case 'b':
+		n := uint64(0)
+		if v.b {
+			n = 1
+		}
+		return appendVarintField(b, 2, n)
+	case 'i':
+		return appendVarintField(b, 3, uint64(v.i))
+	case 'd':
+		return appendFixed64Field(b, 4, math.Float64bits(v.d))
+	default:
+		return appendStringField(b, 1, v.s)
+	}
+}
+
+// KeyValue: key=1 value=2
+func (a otlpAttr) protoKV() []byte {
+	kv := appendStringField(nil, 1, a.key)
+	return appendBytesField(kv, 2, a.value.appendProto(nil))
+}
+
+func (e OTLPEncoder) encodeProto(resources []*otlpResource) []byte {
+	var request []byte
+	for _, res := range resources {
+		// Resource: attributes=1
+		var resource []byte
+		for _, a := range res.attrs {
+			resource = appendBytesField(resource, 1, a.protoKV())
+		}
+
+		// ScopeLogs: scope=1 log_records=2；InstrumentationScope: name=1
+		scope := appendBytesField(nil, 1, appendStringField(nil, 1, otlpScopeName))
+		for _, r := range res.records {
+			scope = appendBytesField(scope, 2, r.appendProto(nil))
+		}
+
+		// ResourceLogs: resource=1 scope_logs=2
+		resourceLogs := appendBytesField(nil, 1, resource)
+		resourceLogs = appendBytesField(resourceLogs, 2, scope)
+
+		// ExportLogsServiceRequest: resource_logs=1
+		request = appendBytesField(request, 1, resourceLogs)
+	}
+	return request
+}
+
+// LogRecord: time_unix_nano=1 severity_number=2 severity_text=3 body=5
+// attributes=6 trace_id=9 span_id=10 observed_time_unix_nano=11
+func (r otlpRecord) appendProto(b []byte) []byte {
+	if r.time != 0 {
+		b = appendFixed64Field(b, 1, r.time)
+	}
+	b = appendVarintField(b, 2, uint64(r.severity))
+	b = appendStringField(b, 3, r.text)
+	b = appendBytesField(b, 5, otlpValue{kind: 's', s: r.body}.appendProto(nil))
+	for _, a := range r.attrs {
+		b = appendBytesField(b, 6, a.protoKV())
+	}
+	if r.traceID != nil {
+		b = appendBytesField(b, 9, r.traceID)
+	}
+	if r.spanID != nil {
+		b = appendBytesField(b, 10, r.spanID)
+	}
+	b = appendFixed64Field(b, 11, r.observed)
+	return b
+}
+
+// OTLPConfig 配置 OTLP/HTTP 日志导出
+type OTLPConfig struct {
+	Endpoint           string            // collector 地址，如 http://localhost:4318，未包含路径时追加 /v1/logs
+	JSON               bool              // 使用 OTLP/JSON 编码，默认 protobuf
+	ResourceAttributes map[string]string // 附加的资源属性
+	Headers            http.Header       // 额外请求头
+	Gzip               bool              // 使用 gzip 压缩请求体
+	BatchSize          int               // 每批最多条目数，默认 100
+	FlushInterval      time.Duration     // 定时发送间隔，默认 1s
+}
+
+// NewOTLPSink creates an HTTPSink that exports entries to an OTLP/HTTP
+// collector. Batching, retry and gzip behave as in NewHTTPSink.
+func NewOTLPSink(config OTLPConfig) (*HTTPSink, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("otlp sink requires an endpoint")
+	}
+
+	endpoint, err := otlpLogsURL(config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHTTPSink(HTTPConfig{
+		URL: endpoint,
+		Encoder: OTLPEncoder{
+			JSON:               config.JSON,
+			ResourceAttributes: config.ResourceAttributes,
+		},
+		Headers:       config.Headers,
+		Gzip:          config.Gzip,
+		BatchSize:     config.BatchSize,
+		FlushInterval: config.FlushInterval,
+	})
+}
+
+// otlpLogsURL 在 endpoint 没有路径（或只有 "/"）时将路径设为 /v1/logs，查询参数保持不变；
+// 已有路径时原样使用
+func otlpLogsURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid otlp endpoint: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("otlp endpoint %q must be an http or https URL", endpoint)
+	}
+	if strings.Trim(u.Path, "/") != "" {
+		return endpoint, nil
+	}
+	u.Path, u.RawPath = "/v1/logs", ""
+	return u.String(), nil
+}
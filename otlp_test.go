@@ -0,0 +1,313 @@
+package logger
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// otlpCollector 记录收到的导出请求
+type otlpCollector struct {
+	ingestServer
+	paths chan string
+}
+
+func (c *otlpCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.paths <- r.URL.Path
+	c.ingestServer.ServeHTTP(w, r)
+}
+
+func exportOTLP(t *testing.T, json bool, entries ...LogEntry) (string, http.Header) {
+	t.Helper()
+
+	collector := &otlpCollector{paths: make(chan string, 16)}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	sink, err := NewOTLPSink(OTLPConfig{
+		Endpoint:           server.URL,
+		JSON:               json,
+		ResourceAttributes: map[string]string{"deployment.environment": "test"},
+		FlushInterval:      time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewOTLPSink failed: %v", err)
+	}
+	for _, entry := range entries {
+		sink.WriteEntry(entry)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if path := <-collector.paths; path != "/v1/logs" {
+		t.Errorf("Expected POST to /v1/logs, got %s", path)
+	}
+	bodies := collector.received()
+	if len(bodies) != 1 {
+		t.Fatalf("Expected 1 export request, got %d", len(bodies))
+	}
+	return bodies[0], collector.headers[0]
+}
+
+func otlpTestEntries() []LogEntry {
+	ts := time.Unix(1700000000, 123)
+	return []LogEntry{
+		{
+			Timestamp: ts,
+			Level:     LevelWarn,
+			Prefix:    "svc",
+			Logger:    "db",
+			Message:   "slow query",
+			File:      "/src/db.go",
+			Line:      42,
+			Fields: []Field{
+				F("ms", 250),
+				F("cached", false),
+				F(TraceIDKey, "0af7651916cd43dd8448eb211c80319c"),
+				F(SpanIDKey, "b7ad6b7169203331"),
+			},
+		},
+		{Timestamp: ts, Level: LevelError, Prefix: "other", Message: "boom", Fields: []Field{F(TraceIDKey, "not-hex")}},
+	}
+}
+
+func TestOTLPSinkJSON(t *testing.T) {
+	body, header := exportOTLP(t, true, otlpTestEntries()...)
+	if ct := header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected application/json, got %q", ct)
+	}
+
+	type anyValue struct {
+		StringValue *string  `json:"stringValue"`
+		BoolValue   *bool    `json:"boolValue"`
+		IntValue    *string  `json:"intValue"`
+		DoubleValue *float64 `json:"doubleValue"`
+	}
+	type keyValue struct {
+		Key   string   `json:"key"`
+		Value anyValue `json:"value"`
+	}
+	var request struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []keyValue `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				Scope      struct{ Name string } `json:"scope"`
+				LogRecords []struct {
+					TimeUnixNano   string     `json:"timeUnixNano"`
+					SeverityNumber int        `json:"severityNumber"`
+					SeverityText   string     `json:"severityText"`
+					Body           anyValue   `json:"body"`
+					Attributes     []keyValue `json:"attributes"`
+					TraceID        string     `json:"traceId"`
+					SpanID         string     `json:"spanId"`
+				} `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("Invalid OTLP/JSON: %v\n%s", err, body)
+	}
+
+	if len(request.ResourceLogs) != 2 {
+		t.Fatalf("Expected one resource per prefix, got %d", len(request.ResourceLogs))
+	}
+	res := request.ResourceLogs[0]
+	if len(res.Resource.Attributes) != 2 || res.Resource.Attributes[0].Key != "service.name" ||
+		*res.Resource.Attributes[0].Value.StringValue != "svc" {
+		t.Errorf("Expected service.name=svc first, got %+v", res.Resource.Attributes)
+	}
+
+	rec := res.ScopeLogs[0].LogRecords[0]
+	if rec.TimeUnixNano != "1700000000000000123" {
+		t.Errorf("Unexpected timeUnixNano %q", rec.TimeUnixNano)
+	}
+	if rec.SeverityNumber != 13 || rec.SeverityText != LevelWarn {
+		t.Errorf("Expected WARN/13, got %s/%d", rec.SeverityText, rec.SeverityNumber)
+	}
+	if rec.Body.StringValue == nil || *rec.Body.StringValue != "slow query" {
+		t.Errorf("Unexpected body %+v", rec.Body)
+	}
+	if rec.TraceID != "0af7651916cd43dd8448eb211c80319c" || rec.SpanID != "b7ad6b7169203331" {
+		t.Errorf("Unexpected trace context %q/%q", rec.TraceID, rec.SpanID)
+	}
+
+	attrs := make(map[string]anyValue)
+	for _, kv := range rec.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if v := attrs["ms"].IntValue; v == nil || *v != "250" {
+		t.Errorf("Expected ms intValue 250, got %+v", attrs["ms"])
+	}
+	if v := attrs["cached"].BoolValue; v == nil || *v {
+		t.Errorf("Expected cached boolValue false, got %+v", attrs["cached"])
+	}
+	if v := attrs["logger.name"].StringValue; v == nil || *v != "db" {
+		t.Errorf("Expected logger.name=db, got %+v", attrs["logger.name"])
+	}
+	if v := attrs["code.lineno"].IntValue; v == nil || *v != "42" {
+		t.Errorf("Expected code.lineno=42, got %+v", attrs["code.lineno"])
+	}
+	if _, ok := attrs[TraceIDKey]; ok {
+		t.Error("Valid trace_id should not be kept as an attribute")
+	}
+
+	other := request.ResourceLogs[1].ScopeLogs[0].LogRecords[0]
+	if other.TraceID != "" || len(other.Attributes) != 1 || other.Attributes[0].Key != TraceIDKey {
+		t.Errorf("Invalid trace_id should stay an attribute, got %+v", other)
+	}
+}
+
+// protoFields 解析一层 protobuf 消息，返回字段号到原始值的映射
+func protoFields(t *testing.T, b []byte) map[int][][]byte {
+	t.Helper()
+
+	fields := make(map[int][][]byte)
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatalf("Bad protobuf tag")
+		}
+		b = b[n:]
+		num := int(key >> 3)
+
+		var value []byte
+		switch key & 7 {
+		case wireVarint:
+			_, n = binary.Uvarint(b)
+			value, b = b[:n], b[n:]
+		case wireFixed64:
+			value, b = b[:8], b[8:]
+		case wireFixed32:
+			value, b = b[:4], b[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(b)
+			value, b = b[n:n+int(size)], b[n+int(size):]
+		default:
+			t.Fatalf("Unexpected wire type %d", key&7)
+		}
+		fields[num] = append(fields[num], value)
+	}
+	return fields
+}
+
+func TestOTLPSinkProtobuf(t *testing.T) {
+	body, header := exportOTLP(t, false, otlpTestEntries()...)
+	if ct := header.Get("Content-Type"); ct != "application/x-protobuf" {
+		t.Errorf("Expected application/x-protobuf, got %q", ct)
+	}
+
+	request := protoFields(t, []byte(body))
+	if len(request[1]) != 2 {
+		t.Fatalf("Expected 2 ResourceLogs, got %d", len(request[1]))
+	}
+
+	resourceLogs := protoFields(t, request[1][0])
+	resource := protoFields(t, resourceLogs[1][0])
+	serviceName := protoFields(t, resource[1][0])
+	if string(serviceName[1][0]) != "service.name" {
+		t.Errorf("Expected service.name attribute, got %q", serviceName[1][0])
+	}
+	if value := protoFields(t, serviceName[2][0]); string(value[1][0]) != "svc" {
+		t.Errorf("Expected service.name=svc, got %q", value[1][0])
+	}
+
+	scopeLogs := protoFields(t, resourceLogs[2][0])
+	if scope := protoFields(t, scopeLogs[1][0]); string(scope[1][0]) != otlpScopeName {
+		t.Errorf("Unexpected scope name %q", scope[1][0])
+	}
+
+	record := protoFields(t, scopeLogs[2][0])
+	if ts := binary.LittleEndian.Uint64(record[1][0]); ts != 1700000000000000123 {
+		t.Errorf("Unexpected time_unix_nano %d", ts)
+	}
+	if sev, _ := binary.Uvarint(record[2][0]); sev != 13 {
+		t.Errorf("Expected severity_number 13, got %d", sev)
+	}
+	if string(record[3][0]) != LevelWarn {
+		t.Errorf("Expected severity_text WARN, got %q", record[3][0])
+	}
+	if body := protoFields(t, record[5][0]); string(body[1][0]) != "slow query" {
+		t.Errorf("Unexpected body %q", body[1][0])
+	}
+	if len(record[9][0]) != 16 || len(record[10][0]) != 8 {
+		t.Errorf("Expected 16-byte trace_id and 8-byte span_id, got %d/%d", len(record[9][0]), len(record[10][0]))
+	}
+
+	// 第一个属性是 ms=250 (int_value)
+	ms := protoFields(t, record[6][0])
+	if v, _ := binary.Uvarint(protoFields(t, ms[2][0])[3][0]); string(ms[1][0]) != "ms" || v != 250 {
+		t.Errorf("Expected ms=250, got %q=%d", ms[1][0], v)
+	}
+}
+
+func TestOTLPValueTypes(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"x", `{"stringValue":"x"}`},
+		{true, `{"boolValue":true}`},
+		{int64(-3), `{"intValue":"-3"}`},
+		{1.5, `{"doubleValue":1.5}`},
+		{json.Number("7"), `{"intValue":"7"}`},
+		{math.Inf(1), `{"stringValue":"+Inf"}`},
+		{time.Second, `{"stringValue":"1s"}`},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(otlpValueOf(tt.value))
+		if err != nil {
+			t.Fatalf("Marshal %v failed: %v", tt.value, err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%#v: expected %s, got %s", tt.value, tt.want, data)
+		}
+	}
+}
+
+func TestOTLPSinkEndpointPath(t *testing.T) {
+	if _, err := NewOTLPSink(OTLPConfig{}); err == nil {
+		t.Error("Expected error for empty endpoint")
+	}
+
+	sink, err := NewOTLPSink(OTLPConfig{Endpoint: "http://collector:4318/custom/logs"})
+	if err != nil {
+		t.Fatalf("NewOTLPSink failed: %v", err)
+	}
+	defer sink.Close()
+	if !strings.HasSuffix(sink.config.URL, "/custom/logs") {
+		t.Errorf("Explicit path should be kept, got %s", sink.config.URL)
+	}
+}
+
+func TestOTLPLogsURL(t *testing.T) {
+	tests := map[string]string{
+		"http://collector:4318":           "http://collector:4318/v1/logs",
+		"http://collector:4318/":          "http://collector:4318/v1/logs",
+		"https://collector:4318//":        "https://collector:4318/v1/logs",
+		"http://collector:4318?x=1":       "http://collector:4318/v1/logs?x=1",
+		"http://collector:4318/?x=1&y=2":  "http://collector:4318/v1/logs?x=1&y=2",
+		"http://collector:4318/v1/logs":   "http://collector:4318/v1/logs",
+		"http://collector:4318/custom/":   "http://collector:4318/custom/",
+		"https://otlp.example.com/a/logs": "https://otlp.example.com/a/logs",
+	}
+	for endpoint, want := range tests {
+		if got, err := otlpLogsURL(endpoint); err != nil || got != want {
+			t.Errorf("otlpLogsURL(%q) = %q, %v; want %q", endpoint, got, err, want)
+		}
+	}
+
+	for _, endpoint := range []string{"collector:4318", "/v1/logs", "ftp://collector", "http://%zz"} {
+		if got, err := otlpLogsURL(endpoint); err == nil {
+			t.Errorf("otlpLogsURL(%q) = %q, want error", endpoint, got)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultRedactionMask 替换被拒绝字段的值
+const DefaultRedactionMask = "[REDACTED]"
+
+// RedactionRule detects sensitive text in messages and field values.
+// Matches of Pattern are replaced with Mask, which may reference submatches
+// like regexp.Regexp.ReplaceAllString. When Replace is set it is called
+// instead; returning the match unchanged keeps it.
+type RedactionRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Mask    string
+	Replace func(match string) string
+}
+
+// RedactionConfig 配置敏感信息脱敏
+type RedactionConfig struct {
+	// Rules 为 nil 时使用 DefaultRedactionRules()，空切片表示不检测内容
+	Rules []RedactionRule
+	// DenyFields 中的名称按不区分大小写的子串匹配字段名，匹配的字段值整体替换为 Mask。
+	// 为 nil 时使用 DefaultDenyFields
+	DenyFields []string
+	// Mask 默认 DefaultRedactionMask
+	Mask string
+}
+
+// DefaultDenyFields 是默认拒绝输出值的字段名
+var DefaultDenyFields = []string{"password", "passwd", "secret", "authorization", "api_key", "apikey"}
+
+// DefaultRedactionRules returns the builtin detectors: bearer tokens, card
+// numbers that pass the Luhn check, email addresses and AWS access key IDs
+// and secret keys.
+func DefaultRedactionRules() []RedactionRule {
+	return []RedactionRule{
+		{
+			Name:    "bearer",
+			Pattern: regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`),
+			Mask:    "$1 [REDACTED]",
+		},
+		{
+			Name:    "card",
+			Pattern: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+			Replace: func(match string) string {
+				if !luhnValid(match) {
+					return match
+				}
+				return "[REDACTED-CARD]"
+			},
+		},
+		{
+			Name:    "email",
+			Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+			Mask:    "[REDACTED-EMAIL]",
+		},
+		{
+			Name:    "aws-access-key",
+			Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+			Mask:    "[REDACTED-AWS-KEY]",
+		},
+		{
+			Name:    "aws-secret-key",
+			Pattern: regexp.MustCompile(`(?i)(aws_?secret_?access_?key["']?\s*[=:]\s*["']?)[A-Za-z0-9/+=]{40}`),
+			Mask:    "${1}[REDACTED]",
+		},
+	}
+}
+
+// luhnValid 校验卡号，忽略空格和连字符
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// redactor 是生效中的脱敏配置
+type redactor struct {
+	rules []RedactionRule
+	deny  []string
+	mask  string
+}
+
+var activeRedactor atomic.Pointer[redactor]
+
+// SetRedaction enables redaction of log entries. It runs before any output,
+// sink or channel sees the entry and rewrites the message and field values.
+// A nil config disables redaction.
+func SetRedaction(config *RedactionConfig) {
+	if config == nil {
+		activeRedactor.Store(nil)
+		return
+	}
+
+	r := &redactor{rules: config.Rules, mask: config.Mask}
+	if r.rules == nil {
+		r.rules = DefaultRedactionRules()
+	}
+	if r.mask == "" {
+		r.mask = DefaultRedactionMask
+	}
+	deny := config.DenyFields
+	if deny == nil {
+		deny = DefaultDenyFields
+	}
+	for _, name := range deny {
+		r.deny = append(r.deny, strings.ToLower(name))
+	}
+	activeRedactor.Store(r)
+}
+
+// Redact applies the active redaction rules to s
+func Redact(s string) string {
+	r := activeRedactor.Load()
+	if r == nil {
+		return s
+	}
+	return r.redact(s)
+}
+
+// redactEntry 返回脱敏后的条目，字段切片在需要修改时复制，不影响 logger 共享的字段
+func redactEntry(entry LogEntry) LogEntry {
+	r := activeRedactor.Load()
+	if r == nil {
+		return entry
+	}
+
+	entry.Message = r.redact(entry.Message)
+
+	copied := false
+	for i, f := range entry.Fields {
+		var value any
+		if r.denied(f.Key) {
+			value = r.mask
+		} else {
+			s := f.String()
+			redacted := r.redact(s)
+			if redacted == s {
+				continue
+			}
+			value = redacted
+		}
+
+		if !copied {
+			entry.Fields = append([]Field(nil), entry.Fields...)
+			copied = true
+		}
+		entry.Fields[i] = F(f.Key, value)
+	}
+	return entry
+}
+
+func (r *redactor) redact(s string) string {
+	for _, rule := range r.rules {
+		if rule.Replace != nil {
+			s = rule.Pattern.ReplaceAllStringFunc(s, rule.Replace)
+		} else {
+			s = rule.Pattern.ReplaceAllString(s, rule.Mask)
+		}
+	}
+	return s
+}
+
+func (r *redactor) denied(key string) bool {
+	key = strings.ToLower(key)
+	for _, name := range r.deny {
+		if strings.Contains(key, name) {
+			return true
+		}
+	}
+	return false
+}
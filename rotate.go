@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultRotateSize 是 RotatingFile 默认的单个文件大小上限
+const DefaultRotateSize = 100 << 20
+
+// RotatingFile is an io.WriteCloser that appends to a file and rotates it
+// once it would grow beyond MaxSize bytes. Rotated files are renamed to
+// path.1, path.2 and so on, path.1 being the most recent; files beyond
+// MaxBackups are removed. A single write is never split across files.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu           sync.Mutex
+	file         *os.File // 打开失败时为 nil，下次写入时重试
+	size         int64
+	closed       bool
+	rotateFailed bool // 上次轮转失败，已报告警告
+}
+
+// NewRotatingFile opens path for appending, creating it and its directory if
+// needed. maxSize <= 0 uses DefaultRotateSize; maxBackups <= 0 keeps no
+// rotated files.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if path == "" {
+		return nil, fmt.Errorf("rotating file requires a path")
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultRotateSize
+	}
+	if maxBackups < 0 {
+		maxBackups = 0
+	}
+
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write 实现 io.Writer，写入后超过上限前先轮转。轮转失败时报告警告并继续写入当前文件，
+// 下次写入时再次尝试轮转
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			if !r.rotateFailed {
+				warnf("Log file %s rotation failed, still writing to it: %v", r.path, err)
+			}
+			r.rotateFailed = true
+			if r.file == nil {
+				return 0, err
+			}
+		} else {
+			r.rotateFailed = false
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close 关闭当前文件
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// Path 返回当前写入的文件路径
+func (r *RotatingFile) Path() string {
+	return r.path
+}
+
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// rotate 依次将 path.N-1 移动到 path.N，当前文件移动为 path.1 后重新打开。
+// 移动失败时重新打开原文件继续追加，返回失败原因
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	if r.maxBackups == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return r.reopen(err)
+		}
+		return r.open()
+	}
+
+	os.Remove(r.backupName(r.maxBackups))
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(r.backupName(i), r.backupName(i+1)); err != nil && !os.IsNotExist(err) {
+			return r.reopen(err)
+		}
+	}
+	if err := os.Rename(r.path, r.backupName(1)); err != nil && !os.IsNotExist(err) {
+		return r.reopen(err)
+	}
+	return r.open()
+}
+
+// reopen 在轮转失败后重新打开当前路径，返回轮转的错误
+func (r *RotatingFile) reopen(err error) error {
+	if openErr := r.open(); openErr != nil {
+		return fmt.Errorf("%w; reopen failed: %v", err, openErr)
+	}
+	return err
+}
+
+func (r *RotatingFile) backupName(i int) string {
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
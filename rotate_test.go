@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	r, err := NewRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer r.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(name)
+		if err != nil || string(data) != content {
+			t.Errorf("%s = %q, %v; want %q", filepath.Base(name), data, err, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%s.3 should not exist", filepath.Base(path))
+	}
+}
+
+func TestRotatingFileWithoutBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	r, err := NewRotatingFile(path, 8, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Write([]byte("aaaaaa\n"))
+	r.Write([]byte("bbbbbb\n"))
+	r.Close()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "bbbbbb\n" {
+		t.Errorf("content = %q", data)
+	}
+	matches, _ := filepath.Glob(path + ".*")
+	if len(matches) != 0 {
+		t.Errorf("unexpected backups %v", matches)
+	}
+	if _, err := r.Write([]byte("x")); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("Write after Close = %v, want closed error", err)
+	}
+}
+
+func TestRotatingFileKeepsWritingWhenRotationFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	r, err := NewRotatingFile(path, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var stderr bytes.Buffer
+	updateConfig(func(c *runtimeConfig) error {
+		c.stderr = &stderr
+		return nil
+	})
+	defer updateConfig(func(c *runtimeConfig) error {
+		c.stderr = os.Stderr
+		return nil
+	})
+
+	// path.1 是非空目录，重命名失败
+	if err := os.MkdirAll(filepath.Join(path+".1", "block"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != "aaaaaa\nbbbbbb\ncccccc\n" {
+		t.Errorf("content = %q", data)
+	}
+	if n := strings.Count(stderr.String(), "rotation failed"); n != 1 {
+		t.Errorf("Expected one warning, got %d: %s", n, stderr.String())
+	}
+
+	// 故障消失后恢复轮转
+	os.RemoveAll(path + ".1")
+	r.Write([]byte("dddddd\n"))
+	if data, _ := os.ReadFile(path); string(data) != "dddddd\n" {
+		t.Errorf("content after recovery = %q", data)
+	}
+	if data, _ := os.ReadFile(path + ".1"); string(data) != "aaaaaa\nbbbbbb\ncccccc\n" {
+		t.Errorf("backup = %q", data)
+	}
+}
@@ -0,0 +1,242 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SamplingConfig 配置重复消息采样和按级别限速
+type SamplingConfig struct {
+	// Interval 是采样计数周期，也是输出抑制汇总的周期，默认 1s
+	Interval time.Duration
+
+	// 每个消息模板（级别、logger 和格式字符串相同）在每个周期内前 First 条全部输出，
+	// 之后每 Thereafter 条输出一条；Thereafter 为 0 时丢弃其余消息。
+	// First <= 0 时不做模板采样。
+	First      int
+	Thereafter int
+
+	// RateLimits 按级别名配置令牌桶限速，在模板采样之后生效
+	RateLimits map[string]RateLimit
+}
+
+// RateLimit 描述一个令牌桶
+type RateLimit struct {
+	Rate  float64 // 每秒补充的令牌数
+	Burst int     // 桶容量，默认取 Rate 向上取整
+}
+
+// sampleKey 标识一个消息模板
+type sampleKey struct {
+	level  string
+	logger string
+	format string
+}
+
+// sampleCounter 记录模板在当前周期内的计数
+type sampleCounter struct {
+	seen       int
+	suppressed int
+}
+
+// tokenBucket 是一个级别的限速状态
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// allow 补充令牌并尝试消耗一个
+func (b *tokenBucket) allow(now time.Time) bool {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// sampler 保存采样状态，计数在每个周期结束时清空
+type sampler struct {
+	config SamplingConfig
+
+	mu       sync.Mutex
+	counters map[sampleKey]*sampleCounter
+	buckets  map[string]*tokenBucket
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+var (
+	activeSampler atomic.Pointer[sampler]
+	samplerMutex  sync.Mutex // 串行化 SetSampling
+)
+
+// SetSampling enables sampling and rate limiting of repetitive messages.
+// Suppressed messages are not formatted and capture no stack trace. Every
+// Interval a "suppressed N similar messages" entry is emitted per template
+// that lost messages, to outputs and channels alike. FATAL and levels
+// registered with Always are never sampled. A nil config disables sampling
+// after emitting the pending summaries.
+func SetSampling(config *SamplingConfig) {
+	samplerMutex.Lock()
+	defer samplerMutex.Unlock()
+
+	var s *sampler
+	if config != nil {
+		s = newSampler(*config)
+	}
+
+	if old := activeSampler.Swap(s); old != nil {
+		old.stop()
+	}
+	if s != nil {
+		s.wg.Add(1)
+		go s.run()
+	}
+}
+
+func newSampler(config SamplingConfig) *sampler {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+
+	s := &sampler{
+		config:   config,
+		counters: make(map[sampleKey]*sampleCounter),
+		buckets:  make(map[string]*tokenBucket),
+		done:     make(chan struct{}),
+	}
+
+	now := time.Now()
+	for level, limit := range config.RateLimits {
+		burst := float64(limit.Burst)
+		if burst <= 0 {
+			burst = limit.Rate
+			if burst < 1 {
+				burst = 1
+			}
+		}
+		s.buckets[strings.ToUpper(level)] = &tokenBucket{
+			rate:   limit.Rate,
+			burst:  burst,
+			tokens: burst,
+			last:   now,
+		}
+	}
+	return s
+}
+
+// sampleAllowed 判断一条消息是否应当记录
+func sampleAllowed(spec LevelSpec, name string, format string) bool {
+	s := activeSampler.Load()
+	// 进程随后退出的 FATAL 消息不能丢失
+	if s == nil || spec.Always || spec.Name == LevelFatal {
+		return true
+	}
+	return s.allow(spec.Name, name, format)
+}
+
+func (s *sampler) allow(level, name, format string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := sampleKey{level: level, logger: name, format: format}
+	counter := s.counters[key]
+	if counter == nil {
+		counter = &sampleCounter{}
+		s.counters[key] = counter
+	}
+	counter.seen++
+
+	allowed := true
+	if s.config.First > 0 && counter.seen > s.config.First {
+		allowed = s.config.Thereafter > 0 && (counter.seen-s.config.First)%s.config.Thereafter == 0
+	}
+	if allowed {
+		if bucket := s.buckets[level]; bucket != nil {
+			allowed = bucket.allow(time.Now())
+		}
+	}
+
+	if !allowed {
+		counter.suppressed++
+	}
+	return allowed
+}
+
+// run 在每个周期结束时输出汇总并清空计数
+func (s *sampler) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			s.flush()
+			return
+		case <-ticker.C:
+			s.flush()
+		}
+	}
+}
+
+// stop 停止后台汇总并输出剩余的汇总
+func (s *sampler) stop() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+// flush 输出本周期的抑制汇总，按级别、logger 和模板排序
+func (s *sampler) flush() {
+	s.mu.Lock()
+	counters := s.counters
+	s.counters = make(map[sampleKey]*sampleCounter)
+	s.mu.Unlock()
+
+	keys := make([]sampleKey, 0, len(counters))
+	for key, counter := range counters {
+		if counter.suppressed > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.level != b.level {
+			return a.level < b.level
+		}
+		if a.logger != b.logger {
+			return a.logger < b.logger
+		}
+		return a.format < b.format
+	})
+
+	for _, key := range keys {
+		emitSuppressed(key, counters[key].suppressed)
+	}
+}
+
+// emitSuppressed 以原级别输出一条抑制汇总，不采集堆栈
+func emitSuppressed(key sampleKey, n int) {
+	spec := resolveLevel(key.level)
+	spec.Stack = StackNone
+
+	l := &Logger{name: key.logger}
+	format := "suppressed %d similar messages"
+	entry := l.newEntry(currentConfig(), spec, fmt.Sprintf(format, n))
+	entry.Fields = []Field{F("suppressed", n), F("template", key.format)}
+	emit(spec, format, entry, nil)
+}
@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingFirstThenEveryNth(t *testing.T) {
+	sink := &memorySink{}
+	AddSink("sampling", sink)
+	defer RemoveSink("sampling")
+
+	SetSampling(&SamplingConfig{Interval: time.Hour, First: 3, Thereafter: 5})
+	for i := 0; i < 20; i++ {
+		Error("dependency down: %d", i)
+	}
+	Error("other template")
+	SetSampling(nil)
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	// 前 3 条，之后第 8、13、18 条
+	var kept []string
+	var summary *LogEntry
+	for i, entry := range sink.entries {
+		if strings.HasPrefix(entry.Message, "suppressed") {
+			summary = &sink.entries[i]
+			continue
+		}
+		kept = append(kept, entry.Message)
+	}
+	want := []string{
+		"dependency down: 0", "dependency down: 1", "dependency down: 2",
+		"dependency down: 7", "dependency down: 12", "dependency down: 17",
+		"other template",
+	}
+	if strings.Join(kept, "|") != strings.Join(want, "|") {
+		t.Errorf("Unexpected sampled messages:\n got %v\nwant %v", kept, want)
+	}
+
+	if summary == nil {
+		t.Fatal("Expected a suppression summary when sampling is disabled")
+	}
+	if summary.Message != "suppressed 14 similar messages" || summary.Level != LevelError {
+		t.Errorf("Unexpected summary %s %q", summary.Level, summary.Message)
+	}
+	if len(summary.StackTrace) != 0 {
+		t.Error("Summary should not capture a stack trace")
+	}
+	if len(summary.Fields) != 2 || summary.Fields[1].Value != "dependency down: %d" {
+		t.Errorf("Unexpected summary fields %+v", summary.Fields)
+	}
+}
+
+func TestSamplingRateLimitPerLevel(t *testing.T) {
+	ch := GetLogChannelWithConfig("sampling-test", LogChannelConfig{BufferSize: 100})
+	defer RemoveLogChannel("sampling-test")
+
+	SetSampling(&SamplingConfig{
+		Interval:   time.Hour,
+		RateLimits: map[string]RateLimit{"warn": {Rate: 0.001, Burst: 2}},
+	})
+	for i := 0; i < 5; i++ {
+		Warn("limited %d", i)
+		Info("unlimited %d", i)
+	}
+	SetSampling(nil)
+
+	warns, infos, summaries := 0, 0, 0
+	for len(ch) > 0 {
+		entry := <-ch
+		switch {
+		case strings.HasPrefix(entry.Message, "suppressed"):
+			summaries++
+			if entry.Message != "suppressed 3 similar messages" {
+				t.Errorf("Unexpected summary %q", entry.Message)
+			}
+		case entry.Level == LevelWarn:
+			warns++
+		case entry.Level == LevelInfo:
+			infos++
+		}
+	}
+
+	if warns != 2 || infos != 5 || summaries != 1 {
+		t.Errorf("Expected 2 WARN, 5 INFO, 1 summary on channel; got %d, %d, %d", warns, infos, summaries)
+	}
+}
+
+func TestSamplingPeriodicSummary(t *testing.T) {
+	sink := &memorySink{}
+	AddSink("sampling-periodic", sink)
+	defer RemoveSink("sampling-periodic")
+
+	SetSampling(&SamplingConfig{Interval: 20 * time.Millisecond, First: 1})
+	defer SetSampling(nil)
+
+	Named("flappy").Warn("retrying")
+	Named("flappy").Warn("retrying")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		sink.mu.Lock()
+		n := len(sink.entries)
+		var last LogEntry
+		if n > 0 {
+			last = sink.entries[n-1]
+		}
+		sink.mu.Unlock()
+
+		if n == 2 {
+			if last.Message != "suppressed 1 similar messages" || last.Logger != "flappy" {
+				t.Errorf("Unexpected summary %+v", last)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected periodic summary, got %d entries", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 新周期重新计数
+	Named("flappy").Warn("retrying")
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if last := sink.entries[len(sink.entries)-1]; last.Message != "retrying" {
+		t.Errorf("Counter should reset each interval, got %q", last.Message)
+	}
+}
+
+func TestSamplingSkipsAlwaysLevels(t *testing.T) {
+	spec := LevelSpec{Name: "NOTICE", Severity: 9, Always: true}
+	s := newSampler(SamplingConfig{First: 1})
+	activeSampler.Store(s)
+	defer activeSampler.Store(nil)
+
+	for i := 0; i < 3; i++ {
+		if !sampleAllowed(spec, "", "level changed") {
+			t.Fatal("Always levels must not be sampled")
+		}
+		if !sampleAllowed(resolveLevel(LevelFatal), "", "exiting") {
+			t.Fatal("FATAL must not be sampled")
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"fmt"
+)
+
+// LogValuer is implemented by values that control how they appear in logs.
+// Arguments and field values implementing it are replaced by the result of
+// LogValue before formatting, so the original value never reaches outputs,
+// sinks or channels.
+type LogValuer interface {
+	LogValue() any
+}
+
+// maxLogValueDepth 限制 LogValue 的递归解析深度，防止返回自身的实现死循环
+const maxLogValueDepth = 8
+
+// resolveLogValue 反复调用 LogValue 直到得到普通值
+func resolveLogValue(v any) any {
+	for i := 0; i < maxLogValueDepth; i++ {
+		lv, ok := v.(LogValuer)
+		if !ok {
+			return v
+		}
+		v = lv.LogValue()
+	}
+	return v
+}
+
+// resolveArgs 解析格式化参数中的 LogValuer，没有时返回原切片
+func resolveArgs(args []any) []any {
+	for i, arg := range args {
+		if _, ok := arg.(LogValuer); !ok {
+			continue
+		}
+
+		resolved := make([]any, len(args))
+		copy(resolved, args[:i])
+		for j := i; j < len(args); j++ {
+			resolved[j] = resolveLogValue(args[j])
+		}
+		return resolved
+	}
+	return args
+}
+
+// resolveFields 解析字段值中的 LogValuer，需要修改时复制切片
+func resolveFields(fields []Field) []Field {
+	for i, f := range fields {
+		if _, ok := f.Value.(LogValuer); !ok {
+			continue
+		}
+
+		resolved := make([]Field, len(fields))
+		copy(resolved, fields)
+		for j := i; j < len(fields); j++ {
+			resolved[j].Value = resolveLogValue(fields[j].Value)
+		}
+		return resolved
+	}
+	return fields
+}
+
+// Secret is a string that always renders as [REDACTED]: with every fmt verb,
+// as JSON, as text and as a log value. Use string(s) to get the value.
+//
+// fmt only consults the methods of exported struct fields, so a Secret
+// stored in an unexported field of a struct passed to %v is printed as is;
+// such structs should implement LogValuer.
+type Secret string
+
+// LogValue 实现 LogValuer
+func (s Secret) LogValue() any {
+	return DefaultRedactionMask
+}
+
+// String 实现 fmt.Stringer
+func (s Secret) String() string {
+	return DefaultRedactionMask
+}
+
+// GoString 实现 fmt.GoStringer，用于 %#v
+func (s Secret) GoString() string {
+	return `logger.Secret("` + DefaultRedactionMask + `")`
+}
+
+// Format 实现 fmt.Formatter，使 %x、%q 等动词同样输出掩码
+func (s Secret) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprint(f, s.GoString())
+	case verb == 'q':
+		fmt.Fprintf(f, "%q", DefaultRedactionMask)
+	default:
+		fmt.Fprint(f, DefaultRedactionMask)
+	}
+}
+
+// MarshalText 实现 encoding.TextMarshaler
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(DefaultRedactionMask), nil
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + DefaultRedactionMask + `"`), nil
+}
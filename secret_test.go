@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type dbConfig struct {
+	Host     string
+	Password Secret
+}
+
+// apiClient 通过 LogValuer 控制自身的日志输出
+type apiClient struct {
+	endpoint string
+	token    string
+}
+
+func (c apiClient) LogValue() any {
+	return fmt.Sprintf("apiClient{endpoint:%s}", c.endpoint)
+}
+
+func TestSecretFormatting(t *testing.T) {
+	s := Secret("hunter2")
+	for _, format := range []string{"%v", "%s", "%q", "%x", "%#v", "%+v", "%10s"} {
+		if out := fmt.Sprintf(format, s); strings.Contains(out, "hunter2") || !strings.Contains(out, DefaultRedactionMask) {
+			t.Errorf("%s leaked or lost the mask: %q", format, out)
+		}
+	}
+
+	cfg := dbConfig{Host: "db", Password: "hunter2"}
+	if out := fmt.Sprintf("%+v", cfg); out != "{Host:db Password:[REDACTED]}" {
+		t.Errorf("Unexpected struct rendering %q", out)
+	}
+
+	data, _ := json.Marshal(cfg)
+	if string(data) != `{"Host":"db","Password":"[REDACTED]"}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	if string(s) != "hunter2" {
+		t.Error("Conversion to string should reveal the value")
+	}
+}
+
+func TestLogValuerInMessagesAndFields(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	ch := GetLogChannel("secret-test")
+	defer RemoveLogChannel("secret-test")
+
+	client := apiClient{endpoint: "https://api", token: "tok-123"}
+	With(F("token", Secret("tok-123")), F("client", client)).
+		Info("config %+v client %v", dbConfig{Host: "db", Password: "hunter2"}, client)
+
+	entry := <-ch
+	want := "config {Host:db Password:[REDACTED]} client apiClient{endpoint:https://api}"
+	if entry.Message != want {
+		t.Errorf("Unexpected channel message:\n got %q\nwant %q", entry.Message, want)
+	}
+	if entry.Fields[0].Value != DefaultRedactionMask || entry.Fields[1].Value != "apiClient{endpoint:https://api}" {
+		t.Errorf("Field values not resolved: %+v", entry.Fields)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "tok-123") {
+		t.Errorf("Output leaked a secret: %s", out)
+	}
+}
+
+type selfValuer struct{}
+
+func (v selfValuer) LogValue() any { return v }
+
+func TestResolveLogValueDepth(t *testing.T) {
+	if _, ok := resolveLogValue(selfValuer{}).(selfValuer); !ok {
+		t.Error("Self-referencing LogValuer should stop after the depth limit")
+	}
+
+	args := []any{1, "x"}
+	if got := resolveArgs(args); &got[0] != &args[0] {
+		t.Error("resolveArgs should not copy when there is no LogValuer")
+	}
+}
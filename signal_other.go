@@ -0,0 +1,8 @@
+//go:build !unix
+
+package logger
+
+// WatchLevelSignals is a no-op on platforms without SIGUSR1/SIGUSR2
+func WatchLevelSignals() (stop func()) {
+	return func() {}
+}
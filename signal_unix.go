@@ -0,0 +1,44 @@
+//go:build unix
+
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// WatchLevelSignals cycles the global minimum level at runtime:
+// SIGUSR1 makes logging more verbose (towards TRACE), SIGUSR2 less verbose
+// (towards FATAL). Both wrap around. Every change is logged.
+// The returned function stops watching.
+func WatchLevelSignals() (stop func()) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-sigCh:
+				delta := 1
+				if sig == syscall.SIGUSR1 {
+					delta = -1
+				}
+				from, to := stepLevel(delta)
+				logNotice("log level changed from %s to %s (%v)", from, to, sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigCh)
+			close(done)
+		})
+	}
+}
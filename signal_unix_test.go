@@ -0,0 +1,67 @@
+//go:build unix
+
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// lockedBuffer 是可被多个 goroutine 并发写入的 bytes.Buffer
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchLevelSignals(t *testing.T) {
+	var buf lockedBuffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	SetLevel(LevelInfo)
+	defer SetLevel(LevelDebug)
+
+	stop := WatchLevelSignals()
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitForOutput(t, &buf, "log level changed from INFO to DEBUG")
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	waitForOutput(t, &buf, "log level changed from DEBUG to INFO")
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	waitForOutput(t, &buf, "log level changed from INFO to WARN")
+
+	if GetLevel() != LevelWarn {
+		t.Errorf("Expected level WARN, got %s", GetLevel())
+	}
+}
+
+func waitForOutput(t *testing.T, buf *lockedBuffer, substr string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(buf.String(), substr) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Output %q not found in: %s", substr, buf.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
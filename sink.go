@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sink receives every entry that passes the output level filter, in addition
+// to the text written to the console and custom writer
+type Sink interface {
+	WriteEntry(entry LogEntry) error
+	Close() error
+}
+
+// ObserverSink is a Sink that observes logging rather than ships it, such as
+// the recorders of package loggertest. Added with AddSink, it receives every
+// entry whatever the output level, before repeated messages are collapsed.
+// Entries of loggers returned by WithSink reach only their own sinks, so an
+// observer sees them only when it is one of those sinks.
+type ObserverSink interface {
+	Sink
+	Observer()
+}
+
+var (
+	sinks         map[string]Sink
+	sinkCount     atomic.Int32 // 已注册的 sink 数量，用于无锁判断是否需要构造条目
+	observerCount atomic.Int32 // 其中 ObserverSink 的数量，非零时未启用的级别也要构造条目
+	sinksMutex    sync.RWMutex
+)
+
+func init() {
+	sinks = make(map[string]Sink)
+}
+
+// AddSink 注册指定名称的 sink，同名 sink 会被关闭并替换
+func AddSink(name string, s Sink) {
+	sinksMutex.Lock()
+	old, exists := sinks[name]
+	sinks[name] = s
+	updateSinkCounts()
+	sinksMutex.Unlock()
+
+	if exists && old != s {
+		old.Close()
+	}
+}
+
+// RemoveSink 移除并关闭指定名称的 sink
+func RemoveSink(name string) error {
+	sinksMutex.Lock()
+	s, exists := sinks[name]
+	delete(sinks, name)
+	updateSinkCounts()
+	sinksMutex.Unlock()
+
+	if !exists {
+		return nil
+	}
+	return s.Close()
+}
+
+// SinkNames 返回所有已注册 sink 的名称
+func SinkNames() []string {
+	sinksMutex.RLock()
+	defer sinksMutex.RUnlock()
+
+	names := make([]string, 0, len(sinks))
+	for name := range sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// updateSinkCounts 更新 sink 计数，调用方持有 sinksMutex 写锁
+func updateSinkCounts() {
+	observers := 0
+	for _, s := range sinks {
+		if _, ok := s.(ObserverSink); ok {
+			observers++
+		}
+	}
+	sinkCount.Store(int32(len(sinks)))
+	observerCount.Store(int32(observers))
+}
+
+// writeSinks 将条目写入除 ObserverSink 外的所有 sink，写入失败时记录警告
+func writeSinks(entry LogEntry) {
+	sinksMutex.RLock()
+	defer sinksMutex.RUnlock()
+
+	for name, s := range sinks {
+		if _, ok := s.(ObserverSink); ok {
+			continue
+		}
+		if err := s.WriteEntry(entry); err != nil {
+			warnf("Log sink '%s' write failed: %v", name, err)
+		}
+	}
+}
+
+// writeObservers 将条目写入所有 ObserverSink
+func writeObservers(entry LogEntry) {
+	if observerCount.Load() == 0 {
+		return
+	}
+	sinksMutex.RLock()
+	defer sinksMutex.RUnlock()
+
+	for name, s := range sinks {
+		if _, ok := s.(ObserverSink); !ok {
+			continue
+		}
+		if err := s.WriteEntry(entry); err != nil {
+			warnf("Log sink '%s' write failed: %v", name, err)
+		}
+	}
+}
+
+// exitTimeout 是 Fatal 退出前等待 sink 关闭的最长时间
+var exitTimeout = 5 * time.Second
+
+// osExit 可在测试中替换
+var osExit = os.Exit
+
+// exit 关闭所有已注册的 sink 后退出进程。异步发送的 sink 在 Close 中送出排队的条目，
+// 包括刚记录的 FATAL 条目；关闭最多等待 exitTimeout
+func exit(code int) {
+	sinksMutex.RLock()
+	pending := make([]Sink, 0, len(sinks))
+	for _, s := range sinks {
+		pending = append(pending, s)
+	}
+	sinksMutex.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, s := range pending {
+		wg.Add(1)
+		go func(s Sink) {
+			defer wg.Done()
+			s.Close()
+		}(s)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(exitTimeout):
+		warnf("Log sinks not closed within %v before exit", exitTimeout)
+	}
+	osExit(code)
+}
@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySink 将条目保存在内存中
+type memorySink struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	closed  bool
+}
+
+func (s *memorySink) WriteEntry(entry LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestSinkReceivesEnabledEntries(t *testing.T) {
+	sink := &memorySink{}
+	AddSink("memory", sink)
+
+	SetLevel(LevelInfo)
+	defer SetLevel(LevelDebug)
+
+	Debug("filtered")
+	Info("kept")
+
+	RemoveSink("memory")
+	if !sink.closed {
+		t.Error("RemoveSink should close the sink")
+	}
+
+	if len(sink.entries) != 1 || sink.entries[0].Message != "kept" {
+		t.Errorf("Unexpected entries: %+v", sink.entries)
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+
+	ch := GetLogChannel("fields")
+	defer RemoveLogChannel("fields")
+
+	reqLogger := With(F("request_id", "abc"), F("path", "/a b"))
+	reqLogger.With(F("status", 200)).Info("done")
+
+	if !strings.Contains(buf.String(), `[INFO] done request_id=abc path="/a b" status=200`) {
+		t.Errorf("Fields not rendered: %s", buf.String())
+	}
+
+	entry := <-ch
+	if len(entry.Fields) != 3 || entry.Fields[2].Key != "status" || entry.Fields[2].Value != 200 {
+		t.Errorf("Unexpected fields: %+v", entry.Fields)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"fields":{"request_id":"abc","path":"/a b","status":200}`) {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+
+	var decoded LogEntry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(decoded.Fields) != 3 || decoded.Fields[0].Key != "request_id" || decoded.Fields[2].String() != "200" {
+		t.Errorf("Unexpected decoded fields: %+v", decoded.Fields)
+	}
+}
+
+func TestWithSinkIgnoresOutputLevel(t *testing.T) {
+	global := captureEntries(t)
+	SetLevel(LevelError)
+	defer SetLevel(LevelDebug)
+
+	scoped := &memorySink{}
+	l := Named("scoped").WithSink(scoped).With(F("k", "v"))
+	l.Trace("below the output level")
+	l.Error("enabled")
+	Info("package level")
+
+	if got := scoped.messages(); strings.Join(got, "|") != "TRACE below the output level|ERROR enabled" {
+		t.Errorf("scoped sink entries = %q", got)
+	}
+	if got := global.messages(); strings.Join(got, "|") != "ERROR enabled" {
+		t.Errorf("global sink entries = %q", got)
+	}
+	scoped.mu.Lock()
+	defer scoped.mu.Unlock()
+	if e := scoped.entries[0]; e.Logger != "scoped" || len(e.Fields) != 1 || !strings.HasSuffix(e.File, "sink_test.go") {
+		t.Errorf("scoped entry = %+v", e)
+	}
+}
+
+// observerSink 是用于测试的 ObserverSink
+type observerSink struct {
+	memorySink
+}
+
+func (s *observerSink) Observer() {}
+
+func TestObserverSink(t *testing.T) {
+	global := captureEntries(t)
+	observer := &observerSink{}
+	AddSink("observer", observer)
+	defer RemoveSink("observer")
+	SetLevel(LevelWarn)
+	defer SetLevel(LevelDebug)
+
+	scoped := &memorySink{}
+	Named("observed").Debug("below the output level")
+	Warn("enabled")
+	WithSink(scoped).Error("scoped")
+
+	if got := observer.messages(); strings.Join(got, "|") != "DEBUG below the output level|WARN enabled" {
+		t.Errorf("observer entries = %q", got)
+	}
+	if got := global.messages(); strings.Join(got, "|") != "WARN enabled|ERROR scoped" {
+		t.Errorf("global sink entries = %q", got)
+	}
+	if got := scoped.messages(); strings.Join(got, "|") != "ERROR scoped" {
+		t.Errorf("scoped sink entries = %q", got)
+	}
+}
+
+// blockingSink 的 Close 阻塞到 release 被关闭
+type blockingSink struct {
+	release chan struct{}
+}
+
+func (s *blockingSink) WriteEntry(LogEntry) error { return nil }
+
+func (s *blockingSink) Close() error {
+	<-s.release
+	return nil
+}
+
+func TestFatalClosesSinks(t *testing.T) {
+	sink := captureEntries(t)
+	blocked := &blockingSink{release: make(chan struct{})}
+	AddSink("blocking", blocked)
+	defer func() {
+		close(blocked.release)
+		RemoveSink("blocking")
+	}()
+
+	oldExit, oldTimeout := osExit, exitTimeout
+	defer func() { osExit, exitTimeout = oldExit, oldTimeout }()
+	code := -1
+	osExit = func(c int) { code = c }
+	exitTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	Named("fatal").Fatal("shutting down")
+
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Fatal waited %v for a blocked sink", elapsed)
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if !sink.closed || len(sink.entries) != 1 || sink.entries[0].Level != LevelFatal {
+		t.Errorf("sink closed = %v, entries = %+v", sink.closed, sink.entries)
+	}
+}
@@ -0,0 +1,235 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// spool 是发送失败时暂存帧的有界缓冲区，调用方负责加锁
+type spool interface {
+	push(frame []byte) bool       // 返回 false 表示因容量不足丢弃了某一帧
+	peek() ([]byte, uint64, bool) // 返回队首帧及其编号
+	pop(id uint64)                // 仅当队首帧编号为 id 时移除，防止移除已被替换的帧
+	len() int
+	close() error
+}
+
+// memorySpool 在内存中保存最多 limit 帧，满时丢弃最旧的帧
+type memorySpool struct {
+	frames [][]byte
+	first  uint64 // frames[0] 的编号
+	limit  int
+}
+
+func newMemorySpool(limit int) *memorySpool {
+	return &memorySpool{limit: limit}
+}
+
+func (s *memorySpool) push(frame []byte) bool {
+	if len(s.frames) >= s.limit {
+		s.frames[0] = nil
+		s.frames = append(s.frames[1:], frame)
+		s.first++
+		return false
+	}
+	s.frames = append(s.frames, frame)
+	return true
+}
+
+func (s *memorySpool) peek() ([]byte, uint64, bool) {
+	if len(s.frames) == 0 {
+		return nil, 0, false
+	}
+	return s.frames[0], s.first, true
+}
+
+func (s *memorySpool) pop(id uint64) {
+	if len(s.frames) > 0 && s.first == id {
+		s.frames[0] = nil
+		s.frames = s.frames[1:]
+		s.first++
+	}
+}
+
+func (s *memorySpool) len() int {
+	return len(s.frames)
+}
+
+func (s *memorySpool) close() error {
+	s.frames = nil
+	return nil
+}
+
+// diskSpool 将帧以 "4 字节长度 + 内容" 追加到文件，每次发送成功后将读取位置
+// 写入同名的 .offset 文件。队列清空时截断文件；进程重启后从保存的位置继续，
+// 只有最后一帧已发送但位置未写入时会重复发送。文件达到 maxBytes 后丢弃新帧。
+type diskSpool struct {
+	file     *os.File
+	offset   *os.File // 保存 readPos 的 8 字节文件
+	reader   *bufio.Reader
+	readPos  int64
+	size     int64
+	count    int
+	maxBytes int64
+	head     []byte // 已读取但尚未 pop 的帧
+}
+
+func newDiskSpool(dir string, name string, maxBytes int64) (*diskSpool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := os.OpenFile(filepath.Join(dir, name+".offset"), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	s := &diskSpool{file: file, offset: offset, maxBytes: maxBytes}
+	if err := s.recover(); err != nil {
+		file.Close()
+		offset.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// recover 统计上次运行遗留的帧，截掉末尾不完整的记录，并从保存的读取位置继续。
+// 保存的位置不在帧边界上时视为无效，从文件头重放。
+func (s *diskSpool) recover() error {
+	var saved int64 = -1
+	var buf [8]byte
+	if _, err := s.offset.ReadAt(buf[:], 0); err == nil {
+		saved = int64(binary.BigEndian.Uint64(buf[:]))
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(s.file, 0, 1<<62))
+	var offset int64
+	var sent int
+	for {
+		if offset == saved {
+			sent = s.count
+		}
+		var header [4]byte
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			break
+		}
+		n := int64(binary.BigEndian.Uint32(header[:]))
+		if _, err := reader.Discard(int(n)); err != nil {
+			break
+		}
+		offset += 4 + n
+		s.count++
+	}
+
+	if offset == saved {
+		sent = s.count
+	}
+
+	s.size = offset
+	if sent > 0 && sent == s.count {
+		// 所有帧都已发送，直接清空
+		s.count = 0
+		s.size = 0
+	} else if sent > 0 {
+		s.count -= sent
+		s.readPos = saved
+	}
+	if err := s.file.Truncate(s.size); err != nil {
+		return err
+	}
+	return s.saveOffset()
+}
+
+// saveOffset 将读取位置写入 offset 文件
+func (s *diskSpool) saveOffset() error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(s.readPos))
+	_, err := s.offset.WriteAt(buf[:], 0)
+	return err
+}
+
+func (s *diskSpool) push(frame []byte) bool {
+	if s.maxBytes > 0 && s.size+4+int64(len(frame)) > s.maxBytes {
+		return false
+	}
+
+	record := make([]byte, 4, 4+len(frame))
+	binary.BigEndian.PutUint32(record, uint32(len(frame)))
+	record = append(record, frame...)
+	if _, err := s.file.WriteAt(record, s.size); err != nil {
+		warnf("Log spool write failed: %v", err)
+		return false
+	}
+
+	s.size += int64(len(record))
+	s.count++
+	return true
+}
+
+// 磁盘缓冲从不丢弃队首帧，读取位置即可作为编号
+func (s *diskSpool) peek() ([]byte, uint64, bool) {
+	if s.head != nil {
+		return s.head, uint64(s.readPos), true
+	}
+	if s.count == 0 {
+		return nil, 0, false
+	}
+
+	if s.reader == nil {
+		s.reader = bufio.NewReader(io.NewSectionReader(s.file, s.readPos, 1<<62))
+	}
+
+	var header [4]byte
+	if _, err := io.ReadFull(s.reader, header[:]); err != nil {
+		return nil, 0, false
+	}
+	frame := make([]byte, binary.BigEndian.Uint32(header[:]))
+	if _, err := io.ReadFull(s.reader, frame); err != nil {
+		return nil, 0, false
+	}
+
+	s.head = frame
+	return frame, uint64(s.readPos), true
+}
+
+func (s *diskSpool) pop(id uint64) {
+	if s.head == nil || uint64(s.readPos) != id {
+		return
+	}
+
+	s.readPos += 4 + int64(len(s.head))
+	s.head = nil
+	s.count--
+
+	// 队列清空后截断文件，避免无限增长
+	if s.count == 0 {
+		s.file.Truncate(0)
+		s.size = 0
+		s.readPos = 0
+		s.reader = nil
+	}
+	if err := s.saveOffset(); err != nil {
+		warnf("Log spool offset write failed: %v", err)
+	}
+}
+
+func (s *diskSpool) len() int {
+	return s.count
+}
+
+func (s *diskSpool) close() error {
+	err := s.file.Close()
+	if offsetErr := s.offset.Close(); err == nil {
+		err = offsetErr
+	}
+	return err
+}
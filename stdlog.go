@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// maxWriterLine 是 Writer 缓冲的未结束行的最大长度，超过时作为一条日志输出
+const maxWriterLine = 64 << 10
+
+// Writer returns an io.WriteCloser that logs every line written to it at
+// the given level, which may be a custom level. Lines are split on '\n'
+// with a trailing '\r' removed and empty lines skipped; a partial line is
+// kept until it is completed, grows beyond 64KB or the writer is closed.
+func Writer(level string) io.WriteCloser {
+	return std.Writer(level)
+}
+
+// Writer 返回以该 logger 记录每一行的 io.WriteCloser
+func (l *Logger) Writer(level string) io.WriteCloser {
+	return &lineWriter{logger: l, spec: resolveLevel(level)}
+}
+
+// StdLogger returns a standard library *log.Logger that writes through
+// Writer(level). Its prefix and flags are empty, the entries carry the
+// timestamp and prefix of this package.
+func StdLogger(level string) *log.Logger {
+	return std.StdLogger(level)
+}
+
+// StdLogger 返回写入该 logger 的 *log.Logger
+func (l *Logger) StdLogger(level string) *log.Logger {
+	return log.New(l.Writer(level), "", 0)
+}
+
+// RedirectStdLog sends the output of the standard library log package to
+// this package at INFO level and clears its flags and prefix. The returned
+// function restores the previous output, flags and prefix.
+func RedirectStdLog() (restore func()) {
+	w, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+
+	writer := Writer(LevelInfo)
+	log.SetOutput(writer)
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	return func() {
+		log.SetOutput(w)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		writer.Close()
+	}
+}
+
+// lineWriter 将写入的字节按行拆分后记录
+type lineWriter struct {
+	logger *Logger
+	spec   LevelSpec
+
+	mu     sync.Mutex
+	buf    []byte
+	closed bool
+}
+
+// Write 实现 io.Writer
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			if len(w.buf) >= maxWriterLine {
+				w.flush()
+			}
+			break
+		}
+
+		w.buf = append(w.buf, p[:i]...)
+		w.flush()
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// Close 输出未结束的行，之后的写入返回 os.ErrClosed
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	if len(w.buf) > 0 {
+		w.flush()
+	}
+	w.closed = true
+	return nil
+}
+
+// flush 记录缓冲区中的一行并清空缓冲区，空行被忽略。调用方持有 w.mu
+func (w *lineWriter) flush() {
+	line := bytes.TrimSuffix(w.buf, []byte{'\r'})
+	if len(line) > 0 {
+		w.logger.log(writerCaller, w.spec, string(line), false, nil, nil)
+	}
+	w.buf = w.buf[:0]
+}
+
+// packagePath 是本包的导入路径，用于识别本包的栈帧
+var packagePath = reflect.TypeOf(lineWriter{}).PkgPath()
+
+// setWriterCaller 记录写入 Writer 的代码位置：跳过记录日志本身、lineWriter 的方法
+// 以及标准库 log、fmt、io、bufio 的栈帧
+func (e *LogEntry) setWriterCaller() {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isWriterFrame(frame.Function) {
+			e.File = frame.File
+			e.Line = frame.Line
+			e.Function = frame.Function
+			return
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// isWriterFrame 判断函数是否属于写入 Writer 与记录日志之间的调用链
+func isWriterFrame(function string) bool {
+	if name, ok := trimPackage(function, packagePath); ok {
+		return strings.HasPrefix(name, "(*lineWriter).") ||
+			name == "(*Logger).log" || name == "(*LogEntry).setCaller"
+	}
+	for _, pkg := range []string{"log", "fmt", "io", "bufio"} {
+		if _, ok := trimPackage(function, pkg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// trimPackage 在函数全名属于 pkg 时返回去掉包路径后的名称
+func trimPackage(function, pkg string) (string, bool) {
+	if !strings.HasPrefix(function, pkg+".") {
+		return "", false
+	}
+	return function[len(pkg)+1:], true
+}
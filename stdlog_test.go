@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// captureEntries 注册一个内存 sink 并在测试结束时移除
+func captureEntries(t *testing.T) *memorySink {
+	t.Helper()
+	withDiscardOutput(t)
+	sink := &memorySink{}
+	name := "capture-" + t.Name()
+	AddSink(name, sink)
+	t.Cleanup(func() { RemoveSink(name) })
+	return sink
+}
+
+func (s *memorySink) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var messages []string
+	for _, entry := range s.entries {
+		messages = append(messages, entry.Level+" "+entry.Message)
+	}
+	return messages
+}
+
+func TestWriterSplitsLines(t *testing.T) {
+	sink := captureEntries(t)
+
+	w := Writer(LevelWarn)
+	fmt.Fprint(w, "first line\nsecond ")
+	fmt.Fprint(w, "line\r\n\n")
+	w.Write([]byte("partial %d")) // 写入的内容不作为格式字符串
+	if got := len(sink.messages()); got != 2 {
+		t.Fatalf("got %d entries before Close, want 2", got)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"WARN first line", "WARN second line", "WARN partial %d"}
+	if got := sink.messages(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("entries = %q, want %q", got, want)
+	}
+	if _, err := w.Write([]byte("late\n")); err != os.ErrClosed {
+		t.Errorf("Write after Close = %v, want os.ErrClosed", err)
+	}
+}
+
+func TestWriterLongLine(t *testing.T) {
+	sink := captureEntries(t)
+
+	w := Named("writer-long").Writer(LevelInfo)
+	w.Write([]byte(strings.Repeat("x", maxWriterLine+10)))
+	w.Write([]byte("tail\n"))
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.entries) != 2 || len(sink.entries[0].Message) != maxWriterLine+10 || sink.entries[1].Message != "tail" {
+		t.Fatalf("unexpected entries for long line: %d", len(sink.entries))
+	}
+	if sink.entries[0].Logger != "writer-long" {
+		t.Errorf("Logger = %q, want writer-long", sink.entries[0].Logger)
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	sink := captureEntries(t)
+
+	StdLogger(LevelError).Printf("library failed: %s", "disk")
+
+	if got := sink.messages(); len(got) != 1 || got[0] != "ERROR library failed: disk" {
+		t.Errorf("entries = %q", got)
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	sink := captureEntries(t)
+
+	flags, prefix := log.Flags(), log.Prefix()
+	restore := RedirectStdLog()
+	log.Print("from the standard library")
+	restore()
+
+	if got := sink.messages(); len(got) != 1 || got[0] != "INFO from the standard library" {
+		t.Errorf("entries = %q", got)
+	}
+	if log.Flags() != flags || log.Prefix() != prefix {
+		t.Errorf("restore did not reset flags and prefix")
+	}
+}
+
+func TestWriterCaller(t *testing.T) {
+	sink := captureEntries(t)
+
+	w := Writer(LevelInfo)
+	var lines []int
+	line := func() int {
+		_, _, l, _ := runtime.Caller(1)
+		return l
+	}
+
+	// 每次写入前记录下一行的行号
+	lines = append(lines, line()+1)
+	StdLogger(LevelWarn).Print("std logger")
+	lines = append(lines, line()+1)
+	fmt.Fprintln(w, "fmt")
+	lines = append(lines, line()+1)
+	w.Write([]byte("direct\n"))
+	w.Write([]byte("on close"))
+	lines = append(lines, line()+1)
+	w.Close()
+
+	restore := RedirectStdLog()
+	lines = append(lines, line()+1)
+	log.Printf("std log")
+	restore()
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.entries) != len(lines) {
+		t.Fatalf("got %d entries, want %d", len(sink.entries), len(lines))
+	}
+	for i, entry := range sink.entries {
+		if filepath.Base(entry.File) != "stdlog_test.go" || entry.Line != lines[i] ||
+			!strings.HasSuffix(entry.Function, ".TestWriterCaller") {
+			t.Errorf("%q: caller %s:%d %s, want stdlog_test.go:%d", entry.Message, entry.File, entry.Line, entry.Function, lines[i])
+		}
+	}
+}
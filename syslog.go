@@ -0,0 +1,341 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SyslogFormat 选择 syslog 消息格式
+type SyslogFormat int
+
+// SyslogFormat constants
+const (
+	RFC5424 SyslogFormat = iota
+	RFC3164
+)
+
+// syslog severity
+const (
+	syslogCrit    = 2
+	syslogErr     = 3
+	syslogWarning = 4
+	syslogNotice  = 5
+	syslogInfo    = 6
+	syslogDebug   = 7
+)
+
+const (
+	// FacilityUser 是默认的 syslog facility
+	FacilityUser = 1
+	// FacilityLocal0 是第一个本地 facility，local1-local7 依次递增
+	FacilityLocal0 = 16
+
+	syslogWriteTimeout = 5 * time.Second
+	syslogMinBackoff   = 100 * time.Millisecond
+	syslogMaxBackoff   = 30 * time.Second
+)
+
+// SyslogConfig 配置 syslog sink
+type SyslogConfig struct {
+	Network  string       // "udp"、"tcp"、"unix" 或 "unixgram"，为空时连接本机 syslog
+	Address  string       // 远程地址或 unix socket 路径
+	Format   SyslogFormat // 消息格式，默认 RFC 5424
+	Facility int          // syslog facility，默认 FacilityUser
+	Hostname string       // HOSTNAME 字段，默认 os.Hostname()
+	AppName  string       // APP-NAME 字段，默认使用 SetProductName 设置的名称
+	SDID     string       // 结构化字段的 SD-ID，默认 "fields@32473"
+}
+
+// SyslogSinkStats 描述 syslog sink 的状态
+type SyslogSinkStats struct {
+	Connected bool
+	Dropped   uint64 // 连接断开期间丢弃的条目数
+}
+
+// SyslogSink writes entries to a syslog daemon. When the connection fails,
+// entries are dropped and counted while a background goroutine reconnects
+// with exponential backoff, so logging never waits for a dial.
+type SyslogSink struct {
+	config SyslogConfig
+	mu     sync.Mutex
+	conn   net.Conn // 断开期间为 nil
+	stream bool     // 流式连接使用 octet counting 分帧
+
+	reconnecting bool // 后台重连进行中
+	closed       bool
+	done         chan struct{}
+	wg           sync.WaitGroup
+	dropped      atomic.Uint64
+}
+
+// 本机 syslog socket 的常见路径
+var localSyslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// NewSyslogSink connects to the syslog daemon described by config.
+// TCP and unix stream connections use octet-counted framing (RFC 6587),
+// UDP and unixgram send one message per datagram.
+func NewSyslogSink(config SyslogConfig) (*SyslogSink, error) {
+	if config.Facility == 0 {
+		config.Facility = FacilityUser
+	}
+	if config.Facility < 0 || config.Facility > 23 {
+		return nil, fmt.Errorf("invalid syslog facility %d", config.Facility)
+	}
+	if config.Hostname == "" {
+		config.Hostname, _ = os.Hostname()
+	}
+	if config.SDID == "" {
+		config.SDID = "fields@32473"
+	}
+
+	conn, stream, err := dialSyslog(config)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{config: config, conn: conn, stream: stream, done: make(chan struct{})}, nil
+}
+
+// dialSyslog 建立连接，stream 表示是否为流式连接
+func dialSyslog(config SyslogConfig) (conn net.Conn, stream bool, err error) {
+	network, address := config.Network, config.Address
+
+	var candidates [][2]string
+	switch network {
+	case "":
+		for _, path := range localSyslogPaths {
+			candidates = append(candidates, [2]string{"unixgram", path}, [2]string{"unix", path})
+		}
+	case "unix":
+		// 与 log/syslog 一致，优先尝试数据报
+		candidates = [][2]string{{"unixgram", address}, {"unix", address}}
+	default:
+		candidates = [][2]string{{network, address}}
+	}
+
+	var lastErr error
+	for _, c := range candidates {
+		conn, err := net.DialTimeout(c[0], c[1], syslogWriteTimeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		stream := c[0] == "tcp" || c[0] == "tcp4" || c[0] == "tcp6" || c[0] == "unix"
+		return conn, stream, nil
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("no syslog address")
+	}
+	return nil, false, fmt.Errorf("syslog connect failed: %w", lastErr)
+}
+
+// WriteEntry 格式化并发送条目。连接断开时丢弃条目并在后台重连，不在日志路径上拨号；
+// 只有导致断开的那次写入返回错误，避免每条日志都产生警告
+func (s *SyslogSink) WriteEntry(entry LogEntry) error {
+	msg := s.format(entry)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		s.dropped.Add(1)
+		s.startReconnect()
+		return nil
+	}
+	if s.stream {
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+	if _, err := s.conn.Write([]byte(msg)); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		s.dropped.Add(1)
+		s.startReconnect()
+		return fmt.Errorf("%w; dropping entries until reconnected", err)
+	}
+	return nil
+}
+
+// startReconnect 启动后台重连，调用方需持有锁
+func (s *SyslogSink) startReconnect() {
+	if s.reconnecting || s.closed {
+		return
+	}
+	s.reconnecting = true
+	s.wg.Add(1)
+	go s.reconnect()
+}
+
+// reconnect 以指数退避重试连接，成功或 sink 关闭时返回
+func (s *SyslogSink) reconnect() {
+	defer s.wg.Done()
+
+	backoff := syslogMinBackoff
+	for {
+		if s.finishReconnect(dialSyslog(s.config)) {
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-s.done:
+			return
+		}
+		backoff *= 2
+		if backoff > syslogMaxBackoff {
+			backoff = syslogMaxBackoff
+		}
+	}
+}
+
+// finishReconnect 处理一次重连的结果，连接成功或 sink 已关闭时返回 true
+func (s *SyslogSink) finishReconnect(conn net.Conn, stream bool, err error) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.closed:
+		if err == nil {
+			conn.Close()
+		}
+	case err == nil:
+		s.conn, s.stream = conn, stream
+	default:
+		return false
+	}
+	s.reconnecting = false
+	return true
+}
+
+// Stats 返回连接状态和丢弃计数
+func (s *SyslogSink) Stats() SyslogSinkStats {
+	s.mu.Lock()
+	connected := s.conn != nil
+	s.mu.Unlock()
+	return SyslogSinkStats{Connected: connected, Dropped: s.dropped.Load()}
+}
+
+// Close 停止重连并关闭连接
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	var err error
+	if s.conn != nil {
+		err = s.conn.Close()
+		s.conn = nil
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// format 根据配置生成一条 syslog 消息（不含分帧）
+func (s *SyslogSink) format(entry LogEntry) string {
+	pri := s.config.Facility*8 + syslogSeverity(entry.Level)
+
+	appName := s.config.AppName
+	if appName == "" {
+		appName = entry.Prefix
+	}
+
+	message := stripNewline(entry.Message)
+
+	if s.config.Format == RFC3164 {
+		tag := syslogToken(appName, 32)
+		if tag == "-" {
+			tag = "logger"
+		}
+		return fmt.Sprintf("<%d>%s %s %s[%d]: %s%s",
+			pri, entry.Timestamp.Format(time.Stamp), syslogToken(s.config.Hostname, 255),
+			tag, os.Getpid(), message, formatFields(entry.Fields))
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s %s %s",
+		pri, entry.Timestamp.Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogToken(s.config.Hostname, 255), syslogToken(appName, 48), os.Getpid(),
+		syslogToken(entry.Logger, 32), s.structuredData(entry.Fields), message)
+}
+
+// structuredData 将字段编码为一个 SD-ELEMENT，没有字段时返回 NILVALUE
+func (s *SyslogSink) structuredData(fields []Field) string {
+	if len(fields) == 0 {
+		return "-"
+	}
+
+	var b strings.Builder
+	b.WriteByte('[')
+	b.WriteString(s.config.SDID)
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(sdName(f.Key))
+		b.WriteString(`="`)
+		b.WriteString(sdEscaper.Replace(f.String()))
+		b.WriteByte('"')
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// sdEscaper 转义 PARAM-VALUE 中的 '"'、'\' 和 ']'
+var sdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// sdName 将字段名转换为合法的 PARAM-NAME
+func sdName(key string) string {
+	name := []byte(syslogToken(key, 32))
+	for i, c := range name {
+		if c == '=' || c == ']' || c == '"' {
+			name[i] = '_'
+		}
+	}
+	return string(name)
+}
+
+// syslogToken 将字符串限制为可打印 ASCII 且不含空格，空值返回 NILVALUE
+func syslogToken(s string, max int) string {
+	if s == "" {
+		return "-"
+	}
+
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(b) < max; i++ {
+		c := s[i]
+		if c < 33 || c > 126 {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+// syslogSeverity 根据级别的严重程度映射 syslog severity
+func syslogSeverity(level string) int {
+	spec := resolveLevel(level)
+	switch {
+	case spec.Severity >= 21:
+		return syslogCrit
+	case spec.Severity >= 17:
+		return syslogErr
+	case spec.Severity >= 13:
+		return syslogWarning
+	case spec.Severity >= 10:
+		return syslogNotice
+	case spec.Severity >= 9:
+		return syslogInfo
+	default:
+		return syslogDebug
+	}
+}
@@ -0,0 +1,203 @@
+package logger
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogUDPRFC5424(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer pc.Close()
+
+	sink, err := NewSyslogSink(SyslogConfig{
+		Network:  "udp",
+		Address:  pc.LocalAddr().String(),
+		Facility: FacilityLocal0,
+		Hostname: "host1",
+		AppName:  "SyslogApp",
+	})
+	if err != nil {
+		t.Fatalf("NewSyslogSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	err = sink.WriteEntry(LogEntry{
+		Timestamp: time.Date(2026, 2, 12, 18, 28, 0, 0, time.UTC),
+		Level:     LevelError,
+		Message:   "db down",
+		Logger:    "db",
+		Fields:    []Field{F("host", "db-1"), F("quote", `a"b]`)},
+	})
+	if err != nil {
+		t.Fatalf("WriteEntry failed: %v", err)
+	}
+
+	buf := make([]byte, 2048)
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+
+	// local0 (16) * 8 + err (3) = 131
+	want := `<131>1 2026-02-12T18:28:00.000000Z host1 SyslogApp ` + strconv.Itoa(os.Getpid()) +
+		` db [fields@32473 host="db-1" quote="a\"b\]"] db down`
+	if got := string(buf[:n]); got != want {
+		t.Errorf("Unexpected message:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestSyslogTCPOctetCountingAndReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer ln.Close()
+
+	messages := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			// 每个连接只读一条消息后断开，迫使客户端重连
+			size, err := reader.ReadString(' ')
+			if err == nil {
+				n, _ := strconv.Atoi(strings.TrimSpace(size))
+				msg := make([]byte, n)
+				if _, err := reader.Read(msg); err == nil {
+					messages <- string(msg)
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	sink, err := NewSyslogSink(SyslogConfig{Network: "tcp", Address: ln.Addr().String(), Format: RFC3164})
+	if err != nil {
+		t.Fatalf("NewSyslogSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelInfo, Message: "first", Prefix: "TcpApp"})
+	select {
+	case msg := <-messages:
+		if !strings.HasPrefix(msg, "<14>") || !strings.Contains(msg, " TcpApp[") || !strings.HasSuffix(msg, "]: first") {
+			t.Errorf("Unexpected RFC 3164 message: %s", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for first message")
+	}
+
+	// 服务端已断开，持续写入直到重连成功
+	deadline := time.After(3 * time.Second)
+	for {
+		sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelWarn, Message: "again"})
+		select {
+		case msg := <-messages:
+			if !strings.HasSuffix(msg, "]: again") {
+				t.Errorf("Unexpected message after reconnect: %s", msg)
+			}
+			return
+		case <-deadline:
+			t.Fatal("Sink did not reconnect")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func TestSyslogSeverityMapping(t *testing.T) {
+	cases := map[string]int{
+		LevelTrace: syslogDebug,
+		LevelDebug: syslogDebug,
+		LevelInfo:  syslogInfo,
+		LevelWarn:  syslogWarning,
+		LevelError: syslogErr,
+		LevelFatal: syslogCrit,
+	}
+	for level, want := range cases {
+		if got := syslogSeverity(level); got != want {
+			t.Errorf("%s: expected severity %d, got %d", level, want, got)
+		}
+	}
+}
+
+func TestSyslogSinkReceivesLoggedEntries(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer pc.Close()
+
+	sink, err := NewSyslogSink(SyslogConfig{Network: "udp", Address: pc.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("NewSyslogSink failed: %v", err)
+	}
+	AddSink("syslog-test", sink)
+	defer RemoveSink("syslog-test")
+
+	Named("api").With(F("user", 42)).Warn("slow request")
+
+	buf := make([]byte, 2048)
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+
+	msg := string(buf[:n])
+	if !strings.HasPrefix(msg, "<12>1 ") || !strings.HasSuffix(msg, ` api [fields@32473 user="42"] slow request`) {
+		t.Errorf("Unexpected message: %s", msg)
+	}
+}
+
+func TestSyslogDropsWhileDisconnected(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			accepted <- conn
+		}
+	}()
+
+	sink, err := NewSyslogSink(SyslogConfig{Network: "tcp", Address: ln.Addr().String()})
+	if err != nil {
+		t.Fatalf("NewSyslogSink failed: %v", err)
+	}
+	defer sink.Close()
+
+	// 关闭监听和连接后，写入失败一次，之后的条目被丢弃且不阻塞
+	ln.Close()
+	(<-accepted).Close()
+	var failures int
+	start := time.Now()
+	for i := 0; i < 50; i++ {
+		if sink.WriteEntry(LogEntry{Timestamp: time.Now(), Level: LevelInfo, Message: "lost"}) != nil {
+			failures++
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("writes while disconnected took %v", elapsed)
+	}
+	if failures != 1 {
+		t.Errorf("got %d write errors, want 1", failures)
+	}
+	stats := sink.Stats()
+	if stats.Connected || stats.Dropped == 0 {
+		t.Errorf("Stats = %+v, want disconnected with dropped entries", stats)
+	}
+}
@@ -0,0 +1,331 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RFC 6455 常量
+const (
+	wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+
+	// 客户端发来的控制帧负载上限
+	wsMaxControlPayload = 125
+	wsWriteTimeout      = 10 * time.Second
+)
+
+// wsSeq 用于为每个 WebSocket 连接生成唯一的 channel 名称
+var wsSeq atomic.Uint64
+
+// NewWebSocketHandler returns an http.Handler that upgrades the request to a
+// WebSocket and streams every matching LogEntry as a JSON text message.
+//
+// Each connection gets its own log channel, so a slow browser only drops its
+// own oldest entries. The query parameters level, prefix, logger and text
+// filter entries (see EntryFilter) and buffer sets the channel buffer size.
+// The channel is removed when the client disconnects.
+//
+// Browsers send cookies and credentials with cross-site WebSocket
+// requests, so upgrades whose Origin header differs from the request's Host
+// are rejected with 403. Use NewWebSocketHandlerWithConfig to allow other
+// origins. Requests without an Origin header, from non-browser clients,
+// are accepted.
+func NewWebSocketHandler() http.Handler {
+	return NewWebSocketHandlerWithConfig(WebSocketConfig{})
+}
+
+// WebSocketConfig configures NewWebSocketHandlerWithConfig
+type WebSocketConfig struct {
+	// AllowedOrigins 列出除同源外允许连接的 Origin，如 "https://ops.example.com"，
+	// 比较时忽略大小写；"*" 允许任意来源
+	AllowedOrigins []string
+}
+
+// NewWebSocketHandlerWithConfig returns a WebSocket handler like
+// NewWebSocketHandler that also accepts the origins in config.AllowedOrigins
+func NewWebSocketHandlerWithConfig(config WebSocketConfig) http.Handler {
+	allowed := append([]string(nil), config.AllowedOrigins...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveWebSocket(w, r, allowed)
+	})
+}
+
+func serveWebSocket(w http.ResponseWriter, r *http.Request, allowedOrigins []string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	if !checkOrigin(r, allowedOrigins) {
+		http.Error(w, "cross-origin websocket request not allowed", http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	filter, err := filterFromQuery(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	size, err := bufferFromQuery(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 在握手完成前订阅，保证连接建立后的日志不会遗漏
+	name := fmt.Sprintf("ws-%d", wsSeq.Add(1))
+	ch := GetLogChannelWithConfig(name, LogChannelConfig{
+		BufferSize: size,
+		Timeout:    100 * time.Millisecond,
+		Level:      filter.Level,
+	})
+	defer RemoveLogChannel(name)
+
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.close()
+
+	// 读循环负责响应 ping/close 并检测断开
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn.readLoop()
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case entry, ok := <-ch:
+			if !ok {
+				conn.writeFrame(wsOpClose, closePayload(1001))
+				return
+			}
+			if !filter.Match(entry) {
+				continue
+			}
+
+			data, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
+			if err := conn.writeFrame(wsOpText, data); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// checkOrigin 判断请求的 Origin 是否与 Host 同源或在允许列表中，没有 Origin 头时允许
+func checkOrigin(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(a, origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// wsConn 是一个已完成握手的服务端 WebSocket 连接
+type wsConn struct {
+	conn      net.Conn
+	rw        *bufio.ReadWriter
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+}
+
+// upgradeWebSocket 校验握手请求并接管底层连接
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusUpgradeRequired)
+		return nil, errors.New("not a websocket request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusBadRequest)
+		return nil, errors.New("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("missing websocket key")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("response writer cannot be hijacked")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + wsGUID))
+	accept := base64.StdEncoding.EncodeToString(sum[:])
+
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	rw.WriteString("Upgrade: websocket\r\n")
+	rw.WriteString("Connection: Upgrade\r\n")
+	rw.WriteString("Sec-WebSocket-Accept: " + accept + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, rw: rw}, nil
+}
+
+// writeFrame 发送一个不分片、不加掩码的服务端帧
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+	switch n := len(payload); {
+	case n <= 125:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// readLoop 读取客户端帧直到连接关闭，客户端数据帧被忽略
+func (c *wsConn) readLoop() {
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case wsOpClose:
+			c.writeFrame(wsOpClose, payload)
+			return
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readFrame 读取一个客户端帧并去除掩码
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.rw, head[:]); err != nil {
+		return 0, nil, err
+	}
+
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if !masked {
+		return 0, nil, errors.New("client frame is not masked")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return 0, nil, err
+	}
+
+	// 数据帧内容没有用处，直接丢弃
+	if opcode < wsOpClose {
+		_, err := io.CopyN(io.Discard, c.rw, int64(length))
+		return opcode, nil, err
+	}
+	if length > wsMaxControlPayload {
+		return 0, nil, errors.New("control frame too large")
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
+
+func (c *wsConn) close() {
+	c.closeOnce.Do(func() {
+		c.conn.Close()
+	})
+}
+
+// closePayload 构造带状态码的 close 帧负载
+func closePayload(code uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, code)
+}
+
+// headerContains 判断逗号分隔的请求头中是否包含指定值（不区分大小写）
+func headerContains(h http.Header, key, value string) bool {
+	for _, v := range h.Values(key) {
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dialWebSocket 完成客户端握手并返回连接
+func dialWebSocket(t *testing.T, server *httptest.Server, path string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+
+	req := "GET " + path + " HTTP/1.1\r\n" +
+		"Host: localhost\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatalf("Handshake write failed: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("Handshake read failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected 101, got %d", resp.StatusCode)
+	}
+	// RFC 6455 示例中的期望值
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Unexpected Sec-WebSocket-Accept %q", got)
+	}
+
+	return conn, reader
+}
+
+// readServerFrame 读取一个服务端帧
+func readServerFrame(t *testing.T, conn net.Conn, reader *bufio.Reader) (byte, []byte) {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var head [2]byte
+	if _, err := io.ReadFull(reader, head[:]); err != nil {
+		t.Fatalf("Frame read failed: %v", err)
+	}
+
+	length := int(head[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		io.ReadFull(reader, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatalf("Payload read failed: %v", err)
+	}
+	return head[0] & 0x0F, payload
+}
+
+// writeClientFrame 发送一个带掩码的客户端帧
+func writeClientFrame(conn net.Conn, opcode byte, payload []byte) {
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	conn.Write(frame)
+}
+
+func TestWebSocketStreamsFilteredEntries(t *testing.T) {
+	server := httptest.NewServer(NewWebSocketHandler())
+	defer server.Close()
+
+	conn, reader := dialWebSocket(t, server, "/?level=warn&text=disk")
+	defer conn.Close()
+
+	Warn("cpu hot")
+	Info("disk info")
+	Error("disk full")
+
+	opcode, payload := readServerFrame(t, conn, reader)
+	if opcode != wsOpText {
+		t.Fatalf("Expected text frame, got opcode %d", opcode)
+	}
+
+	var entry LogEntry
+	if err := json.Unmarshal(payload, &entry); err != nil {
+		t.Fatalf("Invalid entry JSON: %v", err)
+	}
+	if entry.Level != LevelError || entry.Message != "disk full" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+
+	writeClientFrame(conn, wsOpPing, []byte("hi"))
+	opcode, payload = readServerFrame(t, conn, reader)
+	if opcode != wsOpPong || string(payload) != "hi" {
+		t.Errorf("Expected pong 'hi', got opcode %d %q", opcode, payload)
+	}
+}
+
+func TestWebSocketUnsubscribeOnClose(t *testing.T) {
+	server := httptest.NewServer(NewWebSocketHandler())
+	defer server.Close()
+
+	conn, reader := dialWebSocket(t, server, "/")
+	defer conn.Close()
+
+	if countChannels("ws-") != 1 {
+		t.Fatalf("Expected one websocket channel, got %d", countChannels("ws-"))
+	}
+
+	writeClientFrame(conn, wsOpClose, closePayload(1000))
+	if opcode, _ := readServerFrame(t, conn, reader); opcode != wsOpClose {
+		t.Errorf("Expected close frame, got opcode %d", opcode)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for countChannels("ws-") != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Websocket channel not removed after close")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebSocketRejectsPlainRequest(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewWebSocketHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusUpgradeRequired {
+		t.Errorf("Expected 426, got %d", rec.Code)
+	}
+}
+
+func countChannels(prefix string) int {
+	n := 0
+	for _, s := range GetChannelStats() {
+		if strings.HasPrefix(s.Name, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestWebSocketCheckOrigin(t *testing.T) {
+	tests := []struct {
+		origin  string
+		allowed []string
+		want    bool
+	}{
+		{"", nil, true},
+		{"http://localhost:8080", nil, true},
+		{"http://LOCALHOST:8080", nil, true},
+		{"https://evil.example", nil, false},
+		{"http://localhost:9090", nil, false},
+		{"null", nil, false},
+		{"https://ops.example.com", []string{"https://OPS.example.com"}, true},
+		{"https://evil.example", []string{"https://ops.example.com"}, false},
+		{"https://evil.example", []string{"*"}, true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost:8080/ws", nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		if got := checkOrigin(r, tt.allowed); got != tt.want {
+			t.Errorf("checkOrigin(%q, %q) = %v, want %v", tt.origin, tt.allowed, got, tt.want)
+		}
+	}
+}
+
+func TestWebSocketRejectsCrossOrigin(t *testing.T) {
+	before := countChannels("ws-")
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost/ws", nil)
+		r.Header.Set("Origin", "https://evil.example")
+		return r
+	}
+
+	rec := httptest.NewRecorder()
+	NewWebSocketHandler().ServeHTTP(rec, newRequest())
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403, got %d", rec.Code)
+	}
+	if countChannels("ws-") != before {
+		t.Error("Rejected request subscribed a channel")
+	}
+
+	// 允许列表中的来源通过检查，随后因缺少升级头返回 426
+	rec = httptest.NewRecorder()
+	NewWebSocketHandlerWithConfig(WebSocketConfig{AllowedOrigins: []string{"https://evil.example"}}).ServeHTTP(rec, newRequest())
+	if rec.Code != http.StatusUpgradeRequired {
+		t.Errorf("Expected 426 for an allowed origin, got %d", rec.Code)
+	}
+}
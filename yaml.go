@@ -0,0 +1,296 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine 是去掉注释和缩进后的一行
+type yamlLine struct {
+	indent int
+	text   string
+	num    int // 行号，从 1 开始
+}
+
+// yamlParser 解析配置文件使用的 YAML 子集：缩进表示的映射和列表、"- " 列表项、
+// 标量（带引号的字符串、数字、布尔值、null）以及单行的 [] 流式列表和空映射 {}。
+// 不支持锚点、多文档、块标量和 tab 缩进。
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML 将 YAML 子集解析为 map[string]any、[]any 和标量组成的树
+func parseYAML(data []byte) (any, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimRight(raw, " \r")
+		text := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		indent := len(raw) - len(text)
+		text = stripYAMLComment(text)
+		if text == "" || text == "---" {
+			continue
+		}
+		p.lines = append(p.lines, yamlLine{indent: indent, text: text, num: i + 1})
+	}
+	if len(p.lines) == 0 {
+		return map[string]any{}, nil
+	}
+
+	node, err := p.parseNode(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return node, nil
+}
+
+func (p *yamlParser) parseNode(indent int) (any, error) {
+	if isYAMLListItem(p.lines[p.pos].text) {
+		return p.parseList(indent)
+	}
+	return p.parseMap(indent)
+}
+
+func (p *yamlParser) parseMap(indent int) (any, error) {
+	result := make(map[string]any)
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+		if isYAMLListItem(line.text) {
+			return nil, fmt.Errorf("line %d: unexpected list item", line.num)
+		}
+
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line.num)
+		}
+		if _, dup := result[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.num, key)
+		}
+		p.pos++
+
+		if rest != "" {
+			value, err := parseYAMLScalar(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.num, err)
+			}
+			result[key] = value
+			continue
+		}
+
+		// 值在后续缩进块中；列表允许与键同级缩进
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (next.indent == indent && isYAMLListItem(next.text)) {
+				value, err := p.parseNode(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				result[key] = value
+				continue
+			}
+		}
+		result[key] = nil
+	}
+	return result, nil
+}
+
+func (p *yamlParser) parseList(indent int) (any, error) {
+	result := []any{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent != indent || !isYAMLListItem(line.text) {
+			if line.indent > indent {
+				return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+			}
+			break
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		if rest == "" {
+			p.pos++
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				value, err := p.parseNode(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, value)
+			} else {
+				result = append(result, nil)
+			}
+			continue
+		}
+
+		if _, _, ok := splitYAMLKey(rest); ok || isYAMLListItem(rest) {
+			// "- key: value" 开始一个映射，后续键与第一个键对齐
+			offset := len(line.text) - len(rest)
+			p.lines[p.pos] = yamlLine{indent: indent + offset, text: rest, num: line.num}
+			value, err := p.parseNode(indent + offset)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+			continue
+		}
+
+		value, err := parseYAMLScalar(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.num, err)
+		}
+		result = append(result, value)
+		p.pos++
+	}
+	return result, nil
+}
+
+func isYAMLListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey 拆分 "key: value" 或 "key:"，键可以带引号
+func splitYAMLKey(text string) (key, rest string, ok bool) {
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end < 0 || end+1 >= len(text) || text[end+1] != ':' {
+			return "", "", false
+		}
+		value, err := parseYAMLScalar(text[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		return value.(string), strings.TrimSpace(text[end+2:]), true
+	}
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return "", "", false
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			key = strings.TrimSpace(text[:i])
+			if key == "" {
+				return "", "", false
+			}
+			return key, strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// parseYAMLScalar 解析标量和单行流式列表
+func parseYAMLScalar(s string) (any, error) {
+	switch {
+	case s == "{}":
+		return map[string]any{}, nil
+	case strings.HasPrefix(s, "["):
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("unterminated list %q", s)
+		}
+		items := []any{}
+		inner := strings.TrimSpace(s[1 : len(s)-1])
+		if inner == "" {
+			return items, nil
+		}
+		for _, item := range splitFlowList(inner) {
+			value, err := parseYAMLScalar(strings.TrimSpace(item))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case strings.HasPrefix(s, "{"):
+		return nil, fmt.Errorf("flow mappings are not supported")
+	case s[0] == '"':
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("invalid quoted string %s", s)
+		}
+		return strconv.Unquote(s)
+	case s[0] == '\'':
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("invalid quoted string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+
+	switch s {
+	case "true", "True", "TRUE", "yes", "on":
+		return true, nil
+	case "false", "False", "FALSE", "no", "off":
+		return false, nil
+	case "null", "Null", "NULL", "~":
+		return nil, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return s, nil
+}
+
+// closingQuote 返回 s 开头引号对应的结束引号位置，不存在时返回 -1
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// splitFlowList 按逗号拆分流式列表，忽略引号内的逗号
+func splitFlowList(s string) []string {
+	var items []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			if end := closingQuote(s[i:]); end > 0 {
+				i += end
+			}
+		case ',':
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, s[start:])
+}
+
+// stripYAMLComment 去掉引号外以 "#" 开始的注释
+func stripYAMLComment(text string) string {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '"', '\'':
+			if i > 0 && text[i-1] != ' ' && text[i-1] != '-' && text[i-1] != '[' && text[i-1] != ',' {
+				continue
+			}
+			if end := closingQuote(text[i:]); end > 0 {
+				i += end
+			}
+		case '#':
+			if i == 0 || text[i-1] == ' ' {
+				return strings.TrimRight(text[:i], " ")
+			}
+		}
+	}
+	return text
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	input := `
+name: "quoted: value"
+single: 'it''s'
+count: 3
+ratio: 0.5
+enabled: yes
+empty:
+url: http://example.com:8080/path # 注释
+list: [a, "b, c", 1]
+nested:
+  - plain
+  - key: one
+    other: two
+  -
+    deep:
+    - x
+    - y
+  - - inner
+`
+	got, err := parseYAML([]byte(input))
+	if err != nil {
+		t.Fatalf("parseYAML: %v", err)
+	}
+
+	want := map[string]any{
+		"name":    "quoted: value",
+		"single":  "it's",
+		"count":   int64(3),
+		"ratio":   0.5,
+		"enabled": true,
+		"empty":   nil,
+		"url":     "http://example.com:8080/path",
+		"list":    []any{"a", "b, c", int64(1)},
+		"nested": []any{
+			"plain",
+			map[string]any{"key": "one", "other": "two"},
+			map[string]any{"deep": []any{"x", "y"}},
+			[]any{"inner"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseYAML =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	for _, input := range []string{
+		"a: 1\n  b: 2",
+		"a:\n\t- x",
+		"- a\nb: 1",
+		"a: [1, 2",
+		`a: "unterminated`,
+		"just text",
+	} {
+		if _, err := parseYAML([]byte(input)); err == nil {
+			t.Errorf("parseYAML(%q) succeeded, want error", input)
+		}
+	}
+}